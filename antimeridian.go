@@ -0,0 +1,526 @@
+package geojson
+
+import (
+	"math"
+	"sort"
+)
+
+// NormalizeLongitudes wraps the longitudes of the geometry into [-180, 180] in
+// place, those of nested geometries and of the bbox member included, e.g. 190
+// becomes -170. Longitudes already in range, ±180 included, are left as is.
+func (g *Geometry) NormalizeLongitudes() {
+	if g == nil {
+		return
+	}
+
+	switch g.Type {
+	case GeometryPoint:
+		normalizePosition(g.Point)
+	case GeometryMultiPoint:
+		normalizePositions(g.MultiPoint)
+	case GeometryLineString:
+		normalizePositions(g.LineString)
+	case GeometryMultiLineString:
+		for _, line := range g.MultiLineString {
+			normalizePositions(line)
+		}
+	case GeometryPolygon:
+		for _, ring := range g.Polygon {
+			normalizePositions(ring)
+		}
+	case GeometryMultiPolygon:
+		for _, polygon := range g.MultiPolygon {
+			for _, ring := range polygon {
+				normalizePositions(ring)
+			}
+		}
+	case GeometryCollection:
+		for _, geometry := range g.Geometries {
+			geometry.NormalizeLongitudes()
+		}
+	}
+
+	if len(g.BBox) == 4 || len(g.BBox) == 6 {
+		g.BBox[0] = normalizeLongitude(g.BBox[0])
+		g.BBox[len(g.BBox)/2] = normalizeLongitude(g.BBox[len(g.BBox)/2])
+	}
+}
+
+func normalizePositions(line []Point) {
+	for _, p := range line {
+		normalizePosition(p)
+	}
+}
+
+func normalizePosition(p Point) {
+	if len(p) > 0 {
+		p[0] = normalizeLongitude(p[0])
+	}
+}
+
+func normalizeLongitude(lon float64) float64 {
+	if lon >= -180 && lon <= 180 {
+		return lon
+	}
+
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+
+	return lon - 180
+}
+
+// SplitAntimeridian returns a copy of the geometry cut at the antimeridian as
+// RFC 7946 recommends, collections are split recursively.
+// https://tools.ietf.org/html/rfc7946#section-3.1.9
+// An edge spanning more than 180 degrees of longitude is taken as crossing the
+// antimeridian, the positions where it is cut are interpolated in the lon/lat plane.
+// A LineString or Polygon that is cut becomes a MultiLineString or MultiPolygon,
+// whose pieces end at 180 on one side and start at -180 on the other. The rings
+// of a cut polygon are rewound to RFC 7946, see Rewind.
+// Longitudes must be in [-180, 180], see NormalizeLongitudes. Points, polygons
+// enclosing a pole and big polygons are copied as is.
+func (g *Geometry) SplitAntimeridian() *Geometry {
+	if g == nil {
+		return nil
+	}
+
+	split := &Geometry{Type: g.Type, BBox: g.BBox, CRS: g.CRS, Raw: g.Raw}
+	switch g.Type {
+	case GeometryPoint:
+		split.Point = g.Point
+	case GeometryMultiPoint:
+		split.MultiPoint = append([]Point(nil), g.MultiPoint...)
+	case GeometryLineString:
+		lines := splitLine(g.LineString)
+		if len(lines) == 1 {
+			split.LineString = lines[0]
+			break
+		}
+		split.Type, split.MultiLineString = GeometryMultiLineString, lines
+	case GeometryMultiLineString:
+		if g.MultiLineString != nil {
+			split.MultiLineString = [][]Point{}
+		}
+		for _, line := range g.MultiLineString {
+			split.MultiLineString = append(split.MultiLineString, splitLine(line)...)
+		}
+	case GeometryPolygon:
+		if g.CRS.IsStrictWinding() {
+			split.Polygon = append([][]Point(nil), g.Polygon...)
+			break
+		}
+
+		polygons := splitPolygon(g.Polygon)
+		if len(polygons) == 1 {
+			split.Polygon = polygons[0]
+			break
+		}
+		split.Type, split.MultiPolygon = GeometryMultiPolygon, polygons
+	case GeometryMultiPolygon:
+		if g.MultiPolygon != nil {
+			split.MultiPolygon = [][][]Point{}
+		}
+		for _, polygon := range g.MultiPolygon {
+			split.MultiPolygon = append(split.MultiPolygon, splitPolygon(polygon)...)
+		}
+	case GeometryCollection:
+		if g.Geometries != nil {
+			split.Geometries = make([]*Geometry, len(g.Geometries))
+		}
+		for i, geometry := range g.Geometries {
+			split.Geometries[i] = geometry.SplitAntimeridian()
+		}
+	}
+
+	return split
+}
+
+// crossesAntimeridian returns true if the edge from a to b spans more than 180
+// degrees of longitude.
+func crossesAntimeridian(a, b Point) bool {
+	return len(a) >= 2 && len(b) >= 2 && math.Abs(b[0]-a[0]) > 180
+}
+
+// splitLine cuts the line at the antimeridian, it returns a single copy of the
+// line when it does not cross it.
+func splitLine(line []Point) [][]Point {
+	if line == nil {
+		return [][]Point{nil}
+	}
+
+	var lines [][]Point
+	current := make([]Point, 0, len(line))
+	for i, p := range line {
+		if i > 0 && crossesAntimeridian(line[i-1], p) {
+			a := line[i-1]
+			edge := math.Copysign(180, a[0])
+			c := interpolateAt(a, unwrapTo(p, a[0]), edge)
+
+			current = appendDistinct(current, c)
+			if len(current) > 1 {
+				lines = append(lines, current)
+			}
+
+			current = []Point{withLongitude(c, -edge)}
+		}
+		current = appendDistinct(current, p)
+	}
+
+	if len(current) > 1 || len(lines) == 0 {
+		lines = append(lines, current)
+	}
+
+	return lines
+}
+
+// splitPolygon cuts the polygon at the antimeridian, it returns a single copy of
+// the polygon when it does not cross it or cannot be cut.
+func splitPolygon(polygon [][]Point) [][][]Point {
+	unchanged := [][][]Point{append([][]Point(nil), polygon...)}
+	if !polygonCrossesAntimeridian(polygon) {
+		return unchanged
+	}
+
+	// unwrap the rings so their longitudes are continuous, the holes are moved
+	// next to the shell
+	rings := make([][]Point, 0, len(polygon))
+	for i, ring := range polygon {
+		unwrapped := unwrapRing(ring)
+		if unwrapped == nil {
+			// a ring enclosing a pole never comes back to its first longitude
+			return unchanged
+		}
+
+		if i > 0 {
+			west, east := longitudeRange(rings[0])
+			if unwrapped[0][0] < west {
+				unwrapped = shiftRing(unwrapped, 360)
+			} else if unwrapped[0][0] > east {
+				unwrapped = shiftRing(unwrapped, -360)
+			}
+		}
+
+		if o := Orientation(unwrapped); (o < 0) == (i == 0) && o != 0 {
+			reverseRing(unwrapped)
+		}
+		rings = append(rings, unwrapped)
+	}
+
+	pieces := [][][]Point{rings}
+	for _, edge := range []float64{180, -180} {
+		var next [][][]Point
+		for _, piece := range pieces {
+			inside, outside, ok := splitRings(piece, edge)
+			if !ok {
+				return unchanged
+			}
+
+			next = append(next, inside...)
+			for _, p := range outside {
+				next = append(next, shiftPolygon(p, -2*edge))
+			}
+		}
+		pieces = next
+	}
+
+	return pieces
+}
+
+func polygonCrossesAntimeridian(polygon [][]Point) bool {
+	for _, ring := range polygon {
+		for i := 1; i < len(ring); i++ {
+			if crossesAntimeridian(ring[i-1], ring[i]) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// unwrapRing returns a copy of the ring whose longitudes do not jump at the
+// antimeridian, e.g. 179, -179 becomes 179, 181, or nil if the ring is not closed
+// once unwrapped.
+func unwrapRing(ring []Point) []Point {
+	unwrapped := make([]Point, 0, len(ring))
+	for i, p := range ring {
+		if len(p) < 2 {
+			continue
+		}
+
+		if i > 0 && len(unwrapped) > 0 {
+			p = unwrapTo(p, unwrapped[len(unwrapped)-1][0])
+		} else {
+			p = append(Point(nil), p...)
+		}
+		unwrapped = append(unwrapped, p)
+	}
+
+	if len(unwrapped) < 4 || !pointEqual(unwrapped[0], unwrapped[len(unwrapped)-1]) {
+		return nil
+	}
+
+	return unwrapped
+}
+
+// unwrapTo returns a copy of p with its longitude within 180 degrees of lon.
+func unwrapTo(p Point, lon float64) Point {
+	q := append(Point(nil), p...)
+	for q[0]-lon > 180 {
+		q[0] -= 360
+	}
+	for q[0]-lon < -180 {
+		q[0] += 360
+	}
+
+	return q
+}
+
+// splitRings cuts the polygon with the meridian at lon, it returns the pieces
+// on the side of the meridian closest to 0 and those beyond it. It fails if the
+// rings cannot be linked again, e.g. because they are not valid.
+// The boundary is cut in chains going from one crossing of the meridian to the
+// next one, the crossings sorted by latitude delimit the segments of the meridian
+// inside the polygon in pairs, so every piece is made of chains joined by such segments.
+func splitRings(rings [][]Point, lon float64) (inside, outside [][][]Point, ok bool) {
+	// beyond returns true for the positions of the outer side of the meridian,
+	// a position on the meridian is on the side of the one before it.
+	beyond := func(p Point) bool {
+		if lon > 0 {
+			return p[0] > lon
+		}
+		return p[0] < lon
+	}
+
+	type chain struct {
+		points     []Point
+		beyond     bool
+		start, end int
+		used       bool
+	}
+
+	var (
+		chains    []*chain
+		crossings []Point
+		whole     [][]Point
+	)
+	for _, ring := range rings {
+		ring = openRing(ring)
+		sides := positionSides(ring, lon, beyond)
+		if sides == nil {
+			continue
+		}
+
+		// start walking at the first crossing
+		first := -1
+		for i := range ring {
+			if sides[i] != sides[(i+1)%len(ring)] {
+				first = i
+				break
+			}
+		}
+		if first < 0 {
+			whole = append(whole, append(append([]Point(nil), ring...), ring[0]))
+			continue
+		}
+
+		ringStart := len(crossings)
+		var current *chain
+		for k := 0; k < len(ring); k++ {
+			i := (first + k) % len(ring)
+			a, b := ring[i], ring[(i+1)%len(ring)]
+			if current != nil {
+				current.points = appendDistinct(current.points, a)
+			}
+			if sides[i] == sides[(i+1)%len(ring)] {
+				continue
+			}
+
+			c := interpolateAt(a, b, lon)
+			crossings = append(crossings, c)
+			if current != nil {
+				current.points = appendDistinct(current.points, c)
+				current.end = len(crossings) - 1
+				chains = append(chains, current)
+			}
+			current = &chain{points: []Point{c}, beyond: sides[(i+1)%len(ring)], start: len(crossings) - 1}
+		}
+		// the last chain ends at the first crossing of the ring
+		current.points = appendDistinct(current.points, ring[first])
+		current.points = appendDistinct(current.points, crossings[ringStart])
+		current.end = ringStart
+		chains = append(chains, current)
+	}
+
+	if len(chains) == 0 {
+		if len(whole) == 0 {
+			return nil, nil, true
+		}
+		if beyond(whole[0][0]) {
+			return nil, [][][]Point{whole}, true
+		}
+		return [][][]Point{whole}, nil, true
+	}
+
+	// pair the crossings along the meridian
+	order := make([]int, len(crossings))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return crossings[order[i]][1] < crossings[order[j]][1]
+	})
+	partner := make([]int, len(crossings))
+	for i := 0; i+1 < len(order); i += 2 {
+		partner[order[i]], partner[order[i+1]] = order[i+1], order[i]
+	}
+
+	starting := make(map[int]*chain, len(chains))
+	for _, c := range chains {
+		starting[c.start] = c
+	}
+
+	var shells []struct {
+		ring   []Point
+		beyond bool
+	}
+	for _, c := range chains {
+		if c.used {
+			continue
+		}
+
+		var ring []Point
+		for next := c; !next.used; {
+			if next.beyond != c.beyond {
+				return nil, nil, false
+			}
+			next.used = true
+			for _, p := range next.points {
+				ring = appendDistinct(ring, append(Point(nil), p...))
+			}
+
+			if next = starting[partner[next.end]]; next == nil {
+				return nil, nil, false
+			}
+		}
+		ring = append(ring, append(Point(nil), ring[0]...))
+
+		if len(ring) >= 4 && Orientation(ring) != 0 {
+			shells = append(shells, struct {
+				ring   []Point
+				beyond bool
+			}{ring, c.beyond})
+		}
+	}
+
+	// the rings that were not cut are holes of the piece they lie in
+	polygons := make([][][]Point, len(shells))
+	for i, s := range shells {
+		polygons[i] = [][]Point{s.ring}
+	}
+	for _, hole := range whole {
+		for i, s := range shells {
+			if s.beyond == beyond(hole[0]) && ringContains(s.ring, hole[0]) >= 0 {
+				polygons[i] = append(polygons[i], hole)
+				break
+			}
+		}
+	}
+
+	for i, s := range shells {
+		if s.beyond {
+			outside = append(outside, polygons[i])
+		} else {
+			inside = append(inside, polygons[i])
+		}
+	}
+
+	return inside, outside, true
+}
+
+// positionSides returns for every position whether it lies beyond the meridian,
+// or nil if all the positions are on the meridian.
+func positionSides(ring []Point, lon float64, beyond func(Point) bool) []bool {
+	start := -1
+	for i, p := range ring {
+		if p[0] != lon {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		return nil
+	}
+
+	sides := make([]bool, len(ring))
+	side := beyond(ring[start])
+	for k := 0; k < len(ring); k++ {
+		i := (start + k) % len(ring)
+		if ring[i][0] != lon {
+			side = beyond(ring[i])
+		}
+		sides[i] = side
+	}
+
+	return sides
+}
+
+// interpolateAt returns the position of the segment ab at the longitude lon,
+// the latitude and the altitude are interpolated linearly.
+func interpolateAt(a, b Point, lon float64) Point {
+	t := 0.0
+	if b[0] != a[0] {
+		t = (lon - a[0]) / (b[0] - a[0])
+	}
+
+	p := Point{lon, a[1] + t*(b[1]-a[1])}
+	if len(a) > 2 && len(b) > 2 {
+		p = append(p, a[2]+t*(b[2]-a[2]))
+	}
+
+	return p
+}
+
+func withLongitude(p Point, lon float64) Point {
+	q := append(Point(nil), p...)
+	q[0] = lon
+	return q
+}
+
+// appendDistinct appends p unless it equals the last position of the line.
+func appendDistinct(line []Point, p Point) []Point {
+	if len(line) > 0 && pointEqual(line[len(line)-1], p) {
+		return line
+	}
+
+	return append(line, p)
+}
+
+func longitudeRange(ring []Point) (west, east float64) {
+	west, east = math.Inf(1), math.Inf(-1)
+	for _, p := range ring {
+		west, east = math.Min(west, p[0]), math.Max(east, p[0])
+	}
+
+	return west, east
+}
+
+func shiftRing(ring []Point, dlon float64) []Point {
+	shifted := make([]Point, len(ring))
+	for i, p := range ring {
+		shifted[i] = withLongitude(p, p[0]+dlon)
+	}
+
+	return shifted
+}
+
+func shiftPolygon(polygon [][]Point, dlon float64) [][]Point {
+	shifted := make([][]Point, len(polygon))
+	for i, ring := range polygon {
+		shifted[i] = shiftRing(ring, dlon)
+	}
+
+	return shifted
+}
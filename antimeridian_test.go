@@ -0,0 +1,153 @@
+package geojson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestNormalizeLongitudes(t *testing.T) {
+	g := NewGeometryCollection(
+		NewPoint(Point{190, 10}),
+		NewLineString([]Point{{-180, 0}, {180, 0}, {-190, 5, 3}, {540, 1}, {-900, 2}}),
+	)
+	g.BBox = []float64{170, 0, 200, 10}
+	g.NormalizeLongitudes()
+
+	if !reflect.DeepEqual(g.Geometries[0].Point, Point{-170, 10}) {
+		t.Errorf("incorrect point, got %v", g.Geometries[0].Point)
+	}
+
+	want := []Point{{-180, 0}, {180, 0}, {170, 5, 3}, {-180, 1}, {-180, 2}}
+	if !reflect.DeepEqual(g.Geometries[1].LineString, want) {
+		t.Errorf("incorrect line, got %v", g.Geometries[1].LineString)
+	}
+
+	if !reflect.DeepEqual(g.BBox, []float64{170, 0, -160, 10}) {
+		t.Errorf("incorrect bbox, got %v", g.BBox)
+	}
+}
+
+func TestSplitAntimeridianLine(t *testing.T) {
+	g := NewLineString([]Point{{178, 0}, {179, 10}, {-179, 20}, {-178, 30}, {178, 40}}).SplitAntimeridian()
+	if g.Type != GeometryMultiLineString {
+		t.Fatalf("should be a MultiLineString, got %v", g.Type)
+	}
+
+	want := [][]Point{
+		{{178, 0}, {179, 10}, {180, 15}},
+		{{-180, 15}, {-179, 20}, {-178, 30}, {-180, 35}},
+		{{180, 35}, {178, 40}},
+	}
+	if !reflect.DeepEqual(g.MultiLineString, want) {
+		t.Errorf("incorrect lines, got %v", g.MultiLineString)
+	}
+
+	line := []Point{{170, 0}, {180, 5}, {-170, 10}}
+	g = NewLineString(line).SplitAntimeridian()
+	want = [][]Point{{{170, 0}, {180, 5}}, {{-180, 5}, {-170, 10}}}
+	if !reflect.DeepEqual(g.MultiLineString, want) {
+		t.Errorf("incorrect lines through 180, got %v", g.MultiLineString)
+	}
+
+	g = NewLineString([]Point{{1, 2, 3}, {4, 5, 6}}).SplitAntimeridian()
+	if g.Type != GeometryLineString || !reflect.DeepEqual(g.LineString, []Point{{1, 2, 3}, {4, 5, 6}}) {
+		t.Errorf("should not split, got %+v", g)
+	}
+}
+
+func planarArea(polygons ...[][]Point) float64 {
+	area := 0.0
+	for _, polygon := range polygons {
+		area += math.Abs(planarRingArea(polygon[0]))
+		for _, hole := range polygon[1:] {
+			area -= math.Abs(planarRingArea(hole))
+		}
+	}
+
+	return area / 2
+}
+
+func TestSplitAntimeridianPolygon(t *testing.T) {
+	cases := []struct {
+		name    string
+		polygon [][]Point
+		pieces  int
+		holes   int
+	}{
+		{
+			name:    "square",
+			polygon: [][]Point{{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}}},
+			pieces:  2,
+		},
+		{
+			name:    "clockwise with a hole",
+			polygon: [][]Point{{{170, -10}, {170, 10}, {-170, 10}, {-170, -10}, {170, -10}}, {{172, -1}, {174, -1}, {174, 1}, {172, 1}, {172, -1}}},
+			pieces:  2,
+			holes:   1,
+		},
+		{
+			name: "hole across",
+			polygon: [][]Point{
+				{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+				{{175, -5}, {175, 5}, {-175, 5}, {-175, -5}, {175, -5}},
+			},
+			pieces: 2,
+		},
+		{
+			name: "u shape",
+			polygon: [][]Point{{
+				{170, 0}, {-170, 0}, {-170, 10}, {170, 10}, {170, 8}, {-175, 8}, {-175, 2}, {170, 2}, {170, 0},
+			}},
+			pieces: 3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			original := NewPolygon(c.polygon)
+			g := original.SplitAntimeridian()
+			if g.Type != GeometryMultiPolygon || len(g.MultiPolygon) != c.pieces {
+				t.Fatalf("incorrect pieces, got %v", g.MultiPolygon)
+			}
+
+			if err := g.Validate(); err != nil {
+				t.Errorf("should be valid but got %v", err)
+			}
+
+			holes := 0
+			for _, polygon := range g.MultiPolygon {
+				holes += len(polygon) - 1
+				for _, ring := range polygon {
+					for i := 1; i < len(ring); i++ {
+						if crossesAntimeridian(ring[i-1], ring[i]) {
+							t.Errorf("should not cross the antimeridian, got %v", ring)
+						}
+					}
+				}
+				if !IsCCW(polygon[0]) {
+					t.Errorf("shell should be counter-clockwise, got %v", polygon[0])
+				}
+			}
+			if holes != c.holes {
+				t.Errorf("incorrect number of holes, got %d", holes)
+			}
+
+			// the edges are straight in the lon/lat plane where the polygon is cut
+			if a, b := planarArea(original.Polygon), planarArea(g.MultiPolygon...); math.Abs(a-b) > 1e-9*a {
+				t.Errorf("incorrect area, got %v, want %v", b, a)
+			}
+		})
+	}
+
+	polygon := [][]Point{{{0, 0}, {1, 0}, {1, 1}, {0, 0}}}
+	if g := NewPolygon(polygon).SplitAntimeridian(); g.Type != GeometryPolygon || !reflect.DeepEqual(g.Polygon, polygon) {
+		t.Errorf("should not split, got %+v", g)
+	}
+
+	// a ring around the north pole cannot be cut in pieces
+	polar := [][]Point{{{0, 80}, {90, 80}, {180, 80}, {-90, 80}, {0, 80}}}
+	if g := NewPolygon(polar).SplitAntimeridian(); g.Type != GeometryPolygon || !reflect.DeepEqual(g.Polygon, polar) {
+		t.Errorf("should not split a pole cap, got %+v", g)
+	}
+}
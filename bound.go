@@ -0,0 +1,239 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+)
+
+// Bound is a longitude/latitude bounding box, Min is the south-west corner and
+// Max is the north-east corner.
+// A bound crossing the antimeridian has a west longitude greater than its east
+// longitude, Min[0] > Max[0], as described in RFC 7946 section 5.2.
+// https://tools.ietf.org/html/rfc7946#section-5.2
+// The zero value is an empty bound.
+type Bound struct {
+	Min Point
+	Max Point
+}
+
+// NewBound creates a bound from its west, south, east and north edges.
+func NewBound(west, south, east, north float64) Bound {
+	return Bound{Min: Point{west, south}, Max: Point{east, north}}
+}
+
+// BoundFromBBox creates a bound from a GeoJSON bbox member, the bbox has
+// either 4 elements, [west, south, east, north], or 6 elements with the
+// altitudes, [west, south, min altitude, east, north, max altitude].
+func BoundFromBBox(bbox []float64) (Bound, error) {
+	switch len(bbox) {
+	case 4:
+		return NewBound(bbox[0], bbox[1], bbox[2], bbox[3]), nil
+	case 6:
+		return Bound{Min: Point{bbox[0], bbox[1], bbox[2]}, Max: Point{bbox[3], bbox[4], bbox[5]}}, nil
+	}
+
+	return Bound{}, fmt.Errorf("bbox must have 4 or 6 elements, got %d", len(bbox))
+}
+
+// BBox returns the bound as a GeoJSON bbox member, nil for an empty bound.
+func (b Bound) BBox() []float64 {
+	if b.IsEmpty() {
+		return nil
+	}
+
+	if len(b.Min) > 2 && len(b.Max) > 2 {
+		return []float64{b.Min[0], b.Min[1], b.Min[2], b.Max[0], b.Max[1], b.Max[2]}
+	}
+
+	return []float64{b.Min[0], b.Min[1], b.Max[0], b.Max[1]}
+}
+
+// IsEmpty returns true if the bound does not contain any position.
+func (b Bound) IsEmpty() bool {
+	return len(b.Min) < 2 || len(b.Max) < 2
+}
+
+// West returns the west edge longitude of the bound.
+func (b Bound) West() float64 {
+	return b.Min[0]
+}
+
+// South returns the south edge latitude of the bound.
+func (b Bound) South() float64 {
+	return b.Min[1]
+}
+
+// East returns the east edge longitude of the bound.
+func (b Bound) East() float64 {
+	return b.Max[0]
+}
+
+// North returns the north edge latitude of the bound.
+func (b Bound) North() float64 {
+	return b.Max[1]
+}
+
+// CrossesAntimeridian returns true if the bound spans the 180th meridian.
+func (b Bound) CrossesAntimeridian() bool {
+	return !b.IsEmpty() && b.Min[0] > b.Max[0]
+}
+
+// Contains returns true if the position lies inside the bound or on its edges.
+func (b Bound) Contains(p Point) bool {
+	if b.IsEmpty() || len(p) < 2 {
+		return false
+	}
+
+	if p[1] < b.Min[1] || p[1] > b.Max[1] {
+		return false
+	}
+
+	if b.CrossesAntimeridian() {
+		return p[0] >= b.Min[0] || p[0] <= b.Max[0]
+	}
+
+	return p[0] >= b.Min[0] && p[0] <= b.Max[0]
+}
+
+// Bound returns the smallest bound containing every position of the geometry,
+// nested geometries included. The bbox member of the geometry is not used.
+// When the geometry touches or crosses the antimeridian, i.e. it has a longitude
+// of ±180 or an edge spanning more than 180 degrees of longitude, the bound
+// crossing the antimeridian is returned if it is the narrower one.
+func (g *Geometry) Bound() Bound {
+	var e boundExtender
+	e.geometry(g)
+
+	return e.bound()
+}
+
+// boundExtender accumulates the longitude extent both in [-180, 180] and with
+// the negative longitudes shifted to [180, 360] for the antimeridian crossing
+// interpretation.
+type boundExtender struct {
+	count                   int
+	minLon, maxLon          float64
+	minShifted, maxShifted  float64
+	minLat, maxLat          float64
+	minAlt, maxAlt          float64
+	altitudes, antimeridian bool
+
+	// the longitude of the previous position of a line
+	previousLon float64
+	hasPrevious bool
+}
+
+func (e *boundExtender) geometry(g *Geometry) {
+	if g == nil {
+		return
+	}
+
+	switch g.Type {
+	case GeometryPoint:
+		e.position(g.Point)
+	case GeometryMultiPoint:
+		for _, p := range g.MultiPoint {
+			e.position(p)
+		}
+	case GeometryLineString:
+		e.line(g.LineString)
+	case GeometryMultiLineString:
+		for _, line := range g.MultiLineString {
+			e.line(line)
+		}
+	case GeometryPolygon:
+		for _, ring := range g.Polygon {
+			e.line(ring)
+		}
+	case GeometryMultiPolygon:
+		for _, polygon := range g.MultiPolygon {
+			for _, ring := range polygon {
+				e.line(ring)
+			}
+		}
+	case GeometryCollection:
+		for _, geometry := range g.Geometries {
+			e.geometry(geometry)
+		}
+	}
+}
+
+func (e *boundExtender) line(line []Point) {
+	for i, p := range line {
+		e.linePosition(p, i == 0)
+	}
+}
+
+// linePosition adds a position of a line, first is true for the first position of the line.
+func (e *boundExtender) linePosition(p Point, first bool) {
+	if len(p) < 2 {
+		return
+	}
+
+	if !first && e.hasPrevious && math.Abs(p[0]-e.previousLon) > 180 {
+		e.antimeridian = true
+	}
+	e.previousLon, e.hasPrevious = p[0], true
+
+	e.position(p)
+}
+
+func (e *boundExtender) position(p Point) {
+	if len(p) < 2 {
+		return
+	}
+
+	lon, lat := p[0], p[1]
+	altitude := len(p) > 2 && !math.IsNaN(p[2])
+	if math.Abs(lon) == 180 {
+		e.antimeridian = true
+	}
+
+	shifted := lon
+	if shifted < 0 {
+		shifted += 360
+	}
+
+	if e.count == 0 {
+		e.minLon, e.maxLon = lon, lon
+		e.minShifted, e.maxShifted = shifted, shifted
+		e.minLat, e.maxLat = lat, lat
+		e.altitudes = altitude
+		if e.altitudes {
+			e.minAlt, e.maxAlt = p[2], p[2]
+		}
+		e.count++
+		return
+	}
+
+	e.minLon, e.maxLon = math.Min(e.minLon, lon), math.Max(e.maxLon, lon)
+	e.minShifted, e.maxShifted = math.Min(e.minShifted, shifted), math.Max(e.maxShifted, shifted)
+	e.minLat, e.maxLat = math.Min(e.minLat, lat), math.Max(e.maxLat, lat)
+	if e.altitudes = e.altitudes && altitude; e.altitudes {
+		e.minAlt, e.maxAlt = math.Min(e.minAlt, p[2]), math.Max(e.maxAlt, p[2])
+	}
+	e.count++
+}
+
+func (e *boundExtender) bound() Bound {
+	if e.count == 0 {
+		return Bound{}
+	}
+
+	west, east := e.minLon, e.maxLon
+	if e.antimeridian && e.maxShifted-e.minShifted < east-west {
+		west, east = e.minShifted, e.maxShifted
+		if west > 180 {
+			west -= 360
+		}
+		if east > 180 {
+			east -= 360
+		}
+	}
+
+	if e.altitudes {
+		return Bound{Min: Point{west, e.minLat, e.minAlt}, Max: Point{east, e.maxLat, e.maxAlt}}
+	}
+
+	return NewBound(west, e.minLat, east, e.maxLat)
+}
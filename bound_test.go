@@ -0,0 +1,112 @@
+package geojson
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGeometryBound(t *testing.T) {
+	cases := []struct {
+		name string
+		g    *Geometry
+		want []float64
+	}{
+		{"point", NewPoint(Point{1, 2}), []float64{1, 2, 1, 2}},
+		{"point with altitude", NewPoint(Point{1, 2, 3}), []float64{1, 2, 3, 1, 2, 3}},
+		{"linestring", NewLineString([]Point{{1, 2}, {-3, 4}, {5, -6}}), []float64{-3, -6, 5, 4}},
+		{"multipoint far apart", NewMultiPoint(Point{-170, 0}, Point{170, 1}), []float64{-170, 0, 170, 1}},
+		{"polygon", NewPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}}), []float64{0, 0, 6, 6}},
+		{"crossing line", NewLineString([]Point{{170, 0}, {-170, 10}}), []float64{170, 0, -170, 10}},
+		{
+			// RFC 7946 section 5.2
+			"split multilinestring",
+			NewMultiLineString([]Point{{177, -20}, {180, -16}}, []Point{{-180, -16}, {-178, -20}}),
+			[]float64{177, -20, -178, -16},
+		},
+		{
+			"collection",
+			NewGeometryCollection(NewPoint(Point{1, 2}), NewMultiPolygon([][]Point{{{10, 10}, {11, 10}, {11, 11}, {10, 10}}})),
+			[]float64{1, 2, 11, 11},
+		},
+		{"empty", &Geometry{Type: GeometryCollection}, nil},
+		{"nil", nil, nil},
+	}
+
+	for _, c := range cases {
+		if got := c.g.Bound().BBox(); !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s: incorrect bound, got %v, want %v", c.name, got, c.want)
+		}
+	}
+}
+
+func TestBoundContains(t *testing.T) {
+	b := NewBound(170, -10, -170, 10)
+	if !b.CrossesAntimeridian() {
+		t.Errorf("bound should cross the antimeridian")
+	}
+
+	for _, p := range []Point{{175, 0}, {-175, 0}, {180, 10}, {170, -10}} {
+		if !b.Contains(p) {
+			t.Errorf("bound should contain %v", p)
+		}
+	}
+
+	for _, p := range []Point{{0, 0}, {175, 11}, {-160, 0}} {
+		if b.Contains(p) {
+			t.Errorf("bound should not contain %v", p)
+		}
+	}
+
+	if _, err := BoundFromBBox([]float64{1, 2, 3}); err == nil {
+		t.Errorf("should fail to create a bound from 3 elements")
+	}
+
+	b, err := BoundFromBBox([]float64{1, 2, 3, 4})
+	if err != nil || b.West() != 1 || b.South() != 2 || b.East() != 3 || b.North() != 4 {
+		t.Errorf("incorrect bound, got %v, %v", b, err)
+	}
+}
+
+func TestGeometryBBoxRoundTrip(t *testing.T) {
+	g := NewLineString([]Point{{170, 0}, {-170, 10}})
+	g.BBox = g.Bound().BBox()
+
+	data, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("should marshal to json just fine but got %v", err)
+	}
+
+	if string(data) != `{"type":"LineString","bbox":[170,0,-170,10],"coordinates":[[170,0],[-170,10]]}` {
+		t.Errorf("incorrect json, got %s", data)
+	}
+
+	decoded, err := UnmarshalGeometryRawJSON(data)
+	if err != nil {
+		t.Fatalf("should unmarshal json without issue, err %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.BBox, g.BBox) {
+		t.Errorf("bbox should survive json, got %v", decoded.BBox)
+	}
+
+	data, err = bson.Marshal(g)
+	if err != nil {
+		t.Fatalf("should marshal to bson just fine but got %v", err)
+	}
+
+	decoded, err = UnmarshalGeometry(data)
+	if err != nil {
+		t.Fatalf("should unmarshal bson without issue, err %v", err)
+	}
+
+	if !reflect.DeepEqual(decoded.BBox, g.BBox) {
+		t.Errorf("bbox should survive bson, got %v", decoded.BBox)
+	}
+
+	if _, err := UnmarshalGeometryRawJSON([]byte(`{"type":"Point","bbox":[1,2,3],"coordinates":[1,2]}`)); err == nil {
+		t.Errorf("should fail to unmarshal an invalid bbox")
+	}
+}
@@ -0,0 +1,816 @@
+package geojson
+
+import (
+	"fmt"
+	"reflect"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+var (
+	tGeometry    = reflect.TypeOf(Geometry{})
+	tGeometryPtr = reflect.TypeOf(&Geometry{})
+)
+
+// GeometryCodec is a bsoncodec.ValueCodec for Geometry and *Geometry values.
+// It writes the same documents as MarshalBSON and accepts the same documents as
+// UnmarshalBSON, but it reads and writes the coordinates directly instead of going
+// through an intermediate struct or map, which is much faster for bulk loads.
+// Register it with RegisterGeometryCodec, or use NewRegistry.
+type GeometryCodec struct {
+	// DecodeOptions are used when decoding, the zero value is strict decoding.
+	DecodeOptions DecodeOptions
+
+	// EncodeOptions are used when encoding, the zero value writes the same
+	// documents as MarshalBSON.
+	EncodeOptions EncodeOptions
+}
+
+var _ bsoncodec.ValueCodec = &GeometryCodec{}
+
+// NewGeometryCodec creates a geometry codec decoding with the given options.
+func NewGeometryCodec(opts DecodeOptions) *GeometryCodec {
+	return &GeometryCodec{DecodeOptions: opts}
+}
+
+// RegisterGeometryCodec registers a strict GeometryCodec for both Geometry and
+// *Geometry on the registry builder, taking precedence over MarshalBSON and UnmarshalBSON.
+func RegisterGeometryCodec(rb *bsoncodec.RegistryBuilder) *bsoncodec.RegistryBuilder {
+	codec := &GeometryCodec{}
+	return rb.
+		RegisterTypeEncoder(tGeometry, codec).
+		RegisterTypeEncoder(tGeometryPtr, codec).
+		RegisterTypeDecoder(tGeometry, codec).
+		RegisterTypeDecoder(tGeometryPtr, codec)
+}
+
+// NewRegistry returns the default bson registry with the geometry codec registered,
+// to be used with bson.MarshalWithRegistry, bson.UnmarshalWithRegistry or the
+// SetRegistry option of a mongo client.
+// The codec applies to the geometries held in documents, a *Geometry given directly
+// to bson.Marshal or bson.Unmarshal still goes through MarshalBSON and UnmarshalBSON.
+func NewRegistry() *bsoncodec.Registry {
+	return RegisterGeometryCodec(bson.NewRegistryBuilder()).Build()
+}
+
+// EncodeValue implements bsoncodec.ValueEncoder.
+func (c *GeometryCodec) EncodeValue(_ bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+	if !val.IsValid() || (val.Type() != tGeometry && val.Type() != tGeometryPtr) {
+		return bsoncodec.ValueEncoderError{
+			Name:     "GeometryCodec.EncodeValue",
+			Types:    []reflect.Type{tGeometry, tGeometryPtr},
+			Received: val,
+		}
+	}
+
+	if val.Kind() == reflect.Ptr {
+		if val.IsNil() {
+			return vw.WriteNull()
+		}
+		return geometryWriter{c.EncodeOptions}.writeGeometry(vw, val.Interface().(*Geometry))
+	}
+
+	g := val.Interface().(Geometry)
+	return geometryWriter{c.EncodeOptions}.writeGeometry(vw, &g)
+}
+
+// DecodeValue implements bsoncodec.ValueDecoder.
+func (c *GeometryCodec) DecodeValue(_ bsoncodec.DecodeContext, vr bsonrw.ValueReader, val reflect.Value) error {
+	if !val.CanSet() || (val.Type() != tGeometry && val.Type() != tGeometryPtr) {
+		return bsoncodec.ValueDecoderError{
+			Name:     "GeometryCodec.DecodeValue",
+			Types:    []reflect.Type{tGeometry, tGeometryPtr},
+			Received: val,
+		}
+	}
+
+	if vr.Type() == bsontype.Null {
+		val.Set(reflect.Zero(val.Type()))
+		return vr.ReadNull()
+	}
+
+	g := &Geometry{}
+	if err := newGeometryReader(c.DecodeOptions).readGeometry(vr, g); err != nil {
+		return err
+	}
+
+	if val.Kind() == reflect.Ptr {
+		val.Set(reflect.ValueOf(g))
+	} else {
+		val.Set(reflect.ValueOf(*g))
+	}
+
+	return nil
+}
+
+// geometryWriter encodes geometries to a bsonrw.ValueWriter.
+type geometryWriter struct {
+	opts EncodeOptions
+}
+
+func (w geometryWriter) writeGeometry(vw bsonrw.ValueWriter, g *Geometry) error {
+	if !g.Type.IsKnown() && g.Raw != nil {
+		return bsonrw.Copier{}.CopyValueFromBytes(vw, bsontype.EmbeddedDocument, g.Raw)
+	}
+
+	dw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+
+	ew, err := dw.WriteDocumentElement("type")
+	if err != nil {
+		return err
+	}
+	if err = ew.WriteString(string(g.Type)); err != nil {
+		return err
+	}
+
+	if len(g.BBox) > 0 {
+		if ew, err = dw.WriteDocumentElement("bbox"); err != nil {
+			return err
+		}
+		if err = w.writePosition(ew, g.BBox); err != nil {
+			return err
+		}
+	}
+
+	// nil coordinates and geometries are written as null, like MarshalBSON does
+	switch g.Type {
+	case GeometryPoint:
+		err = writeCoordinates(dw, func(vw bsonrw.ValueWriter) error { return w.writePosition(vw, g.Point) })
+	case GeometryMultiPoint:
+		err = writeCoordinates(dw, func(vw bsonrw.ValueWriter) error { return w.writePositions(vw, g.MultiPoint) })
+	case GeometryLineString:
+		err = writeCoordinates(dw, func(vw bsonrw.ValueWriter) error { return w.writePositions(vw, g.LineString) })
+	case GeometryMultiLineString:
+		err = writeCoordinates(dw, func(vw bsonrw.ValueWriter) error { return w.writePaths(vw, g.MultiLineString) })
+	case GeometryPolygon:
+		err = writeCoordinates(dw, func(vw bsonrw.ValueWriter) error { return w.writePaths(vw, g.Polygon) })
+	case GeometryMultiPolygon:
+		err = writeCoordinates(dw, func(vw bsonrw.ValueWriter) error { return w.writePolygons(vw, g.MultiPolygon) })
+	case GeometryCollection:
+		err = w.writeGeometries(dw, g.Geometries)
+	}
+	if err != nil {
+		return err
+	}
+
+	if g.CRS != nil {
+		if err = writeCRS(dw, g.CRS); err != nil {
+			return err
+		}
+	}
+
+	return dw.WriteDocumentEnd()
+}
+
+func writeCRS(dw bsonrw.DocumentWriter, crs *CRS) error {
+	vw, err := dw.WriteDocumentElement("crs")
+	if err != nil {
+		return err
+	}
+
+	cw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+
+	if vw, err = cw.WriteDocumentElement("type"); err != nil {
+		return err
+	}
+	if err = vw.WriteString(crs.Type); err != nil {
+		return err
+	}
+
+	if vw, err = cw.WriteDocumentElement("properties"); err != nil {
+		return err
+	}
+	pw, err := vw.WriteDocument()
+	if err != nil {
+		return err
+	}
+	if vw, err = pw.WriteDocumentElement("name"); err != nil {
+		return err
+	}
+	if err = vw.WriteString(crs.Properties.Name); err != nil {
+		return err
+	}
+	if err = pw.WriteDocumentEnd(); err != nil {
+		return err
+	}
+
+	return cw.WriteDocumentEnd()
+}
+
+func writeCoordinates(dw bsonrw.DocumentWriter, write func(bsonrw.ValueWriter) error) error {
+	vw, err := dw.WriteDocumentElement("coordinates")
+	if err != nil {
+		return err
+	}
+
+	return write(vw)
+}
+
+func (w geometryWriter) writeGeometries(dw bsonrw.DocumentWriter, geometries []*Geometry) error {
+	vw, err := dw.WriteDocumentElement("geometries")
+	if err != nil {
+		return err
+	}
+
+	if geometries == nil {
+		return vw.WriteNull()
+	}
+
+	aw, err := vw.WriteArray()
+	if err != nil {
+		return err
+	}
+
+	for _, g := range geometries {
+		vw, err := aw.WriteArrayElement()
+		if err != nil {
+			return err
+		}
+
+		if g == nil {
+			err = vw.WriteNull()
+		} else {
+			err = w.writeGeometry(vw, g)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	return aw.WriteArrayEnd()
+}
+
+func (w geometryWriter) writePosition(vw bsonrw.ValueWriter, p []float64) error {
+	if p == nil {
+		return vw.WriteNull()
+	}
+
+	aw, err := vw.WriteArray()
+	if err != nil {
+		return err
+	}
+
+	for _, f := range p {
+		vw, err := aw.WriteArrayElement()
+		if err != nil {
+			return err
+		}
+		if err = w.writeCoordinate(vw, f); err != nil {
+			return err
+		}
+	}
+
+	return aw.WriteArrayEnd()
+}
+
+func (w geometryWriter) writeCoordinate(vw bsonrw.ValueWriter, f float64) error {
+	if !w.opts.Decimal128 {
+		return vw.WriteDouble(f)
+	}
+
+	d, err := decimalFromCoordinate(f)
+	if err != nil {
+		return fmt.Errorf("cannot encode %v as a Decimal128: %w", f, err)
+	}
+
+	return vw.WriteDecimal128(d)
+}
+
+func (w geometryWriter) writePositions(vw bsonrw.ValueWriter, points []Point) error {
+	if points == nil {
+		return vw.WriteNull()
+	}
+
+	aw, err := vw.WriteArray()
+	if err != nil {
+		return err
+	}
+
+	for _, p := range points {
+		vw, err := aw.WriteArrayElement()
+		if err != nil {
+			return err
+		}
+		if err = w.writePosition(vw, p); err != nil {
+			return err
+		}
+	}
+
+	return aw.WriteArrayEnd()
+}
+
+func (w geometryWriter) writePaths(vw bsonrw.ValueWriter, paths [][]Point) error {
+	if paths == nil {
+		return vw.WriteNull()
+	}
+
+	aw, err := vw.WriteArray()
+	if err != nil {
+		return err
+	}
+
+	for _, path := range paths {
+		vw, err := aw.WriteArrayElement()
+		if err != nil {
+			return err
+		}
+		if err = w.writePositions(vw, path); err != nil {
+			return err
+		}
+	}
+
+	return aw.WriteArrayEnd()
+}
+
+func (w geometryWriter) writePolygons(vw bsonrw.ValueWriter, polygons [][][]Point) error {
+	if polygons == nil {
+		return vw.WriteNull()
+	}
+
+	aw, err := vw.WriteArray()
+	if err != nil {
+		return err
+	}
+
+	for _, polygon := range polygons {
+		vw, err := aw.WriteArrayElement()
+		if err != nil {
+			return err
+		}
+		if err = w.writePaths(vw, polygon); err != nil {
+			return err
+		}
+	}
+
+	return aw.WriteArrayEnd()
+}
+
+// geometryReader decodes geometries from a bsonrw.ValueReader, the positions
+// it reads share chunks of memory to limit the number of allocations.
+type geometryReader struct {
+	opts  DecodeOptions
+	chunk []float64
+	depth int
+}
+
+// positionChunkSize is the number of coordinates allocated at once.
+const positionChunkSize = 256
+
+func newGeometryReader(opts DecodeOptions) *geometryReader {
+	return &geometryReader{opts: opts}
+}
+
+func (r *geometryReader) readGeometry(vr bsonrw.ValueReader, g *Geometry) error {
+	if vr.Type() != bsontype.EmbeddedDocument {
+		return &DecodeError{Expected: "geometry", Actual: vr.Type()}
+	}
+
+	if r.opts.Lenient {
+		// keep the document around for the geometries of an unknown type
+		raw, err := bsonrw.Copier{}.CopyDocumentToBytes(vr)
+		if err != nil {
+			return err
+		}
+
+		return r.readGeometryBytes(raw, g)
+	}
+
+	return r.readGeometryDocument(vr, g)
+}
+
+// readGeometryBytes decodes a geometry document, g.Raw refers to doc for a geometry
+// of an unknown type.
+func (r *geometryReader) readGeometryBytes(doc []byte, g *Geometry) error {
+	if err := r.readGeometryDocument(bsonrw.NewBSONDocumentReader(doc), g); err != nil {
+		return err
+	}
+
+	if g.Raw != nil {
+		g.Raw = doc
+	}
+
+	return nil
+}
+
+func (r *geometryReader) readGeometryDocument(vr bsonrw.ValueReader, g *Geometry) error {
+	g.Raw = nil
+
+	dr, err := vr.ReadDocument()
+	if err != nil {
+		return err
+	}
+
+	var (
+		hasType     bool
+		coordinates bsoncore.Value
+		geometries  bsoncore.Value
+	)
+
+	for {
+		key, evr, err := dr.ReadElement()
+		if err == bsonrw.ErrEOD {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		switch key {
+		case "type":
+			if evr.Type() != bsontype.String {
+				return &DecodeError{Path: "type", Expected: "geometry type", Actual: evr.Type()}
+			}
+
+			s, err := evr.ReadString()
+			if err != nil {
+				return err
+			}
+
+			if err = r.setType(g, s); err != nil {
+				return decodeErrorAt(err, "type")
+			}
+			hasType = true
+		case "bbox":
+			if g.BBox, err = r.readBBox(evr); err != nil {
+				return decodeErrorAt(err, "bbox")
+			}
+		case "crs":
+			if g.CRS, err = r.readCRS(evr); err != nil {
+				return decodeErrorAt(err, "crs")
+			}
+		case "coordinates", "geometries":
+			if !hasType {
+				// the type comes later, keep the value to read it then
+				t, data, err := bsonrw.Copier{}.CopyValueToBytes(evr)
+				if err != nil {
+					return err
+				}
+				if key == "coordinates" {
+					coordinates = bsoncore.Value{Type: t, Data: data}
+				} else {
+					geometries = bsoncore.Value{Type: t, Data: data}
+				}
+				continue
+			}
+
+			if err = r.readMember(evr, key, g); err != nil {
+				return err
+			}
+		default:
+			if err = evr.Skip(); err != nil {
+				return err
+			}
+		}
+	}
+
+	if !hasType {
+		return &DecodeError{Path: "type", Expected: "geometry type", Actual: bsontype.Null}
+	}
+
+	for key, value := range map[string]bsoncore.Value{"coordinates": coordinates, "geometries": geometries} {
+		if value.Data == nil {
+			continue
+		}
+
+		evr, err := valueReader(key, value)
+		if err != nil {
+			return err
+		}
+		if err = r.readMember(evr, key, g); err != nil {
+			return err
+		}
+	}
+
+	return r.checkMembers(g)
+}
+
+// valueReader returns a reader positioned on a single value.
+func valueReader(key string, value bsoncore.Value) (bsonrw.ValueReader, error) {
+	doc := bsoncore.BuildDocumentFromElements(nil, bsoncore.AppendValueElement(nil, key, value))
+	dr, err := bsonrw.NewBSONDocumentReader(doc).ReadDocument()
+	if err != nil {
+		return nil, err
+	}
+
+	_, evr, err := dr.ReadElement()
+	return evr, err
+}
+
+func (r *geometryReader) setType(g *Geometry, s string) error {
+	g.Type = GeometryType(s)
+	if g.Type.IsKnown() {
+		return nil
+	}
+
+	if !r.opts.Lenient {
+		return fmt.Errorf("%w %q", ErrUnknownType, s)
+	}
+
+	if known, ok := lookupGeometryType(s); ok {
+		g.Type = known
+		return nil
+	}
+
+	// the caller replaces it with the whole document
+	g.Raw = bson.Raw{}
+	return nil
+}
+
+func (r *geometryReader) readMember(vr bsonrw.ValueReader, key string, g *Geometry) error {
+	var err error
+
+	if g.Raw != nil || (key == "geometries") != (g.Type == GeometryCollection) {
+		// not a member of this type of geometry
+		return vr.Skip()
+	}
+
+	switch g.Type {
+	case GeometryPoint:
+		g.Point, err = r.readPosition(vr)
+	case GeometryMultiPoint:
+		g.MultiPoint, err = r.readPositions(vr)
+	case GeometryLineString:
+		g.LineString, err = r.readPositions(vr)
+	case GeometryMultiLineString:
+		g.MultiLineString, err = r.readPaths(vr)
+	case GeometryPolygon:
+		g.Polygon, err = r.readPaths(vr)
+	case GeometryMultiPolygon:
+		g.MultiPolygon, err = r.readPolygons(vr)
+	case GeometryCollection:
+		g.Geometries, err = r.readGeometries(vr)
+	}
+
+	return decodeErrorAt(err, key)
+}
+
+// checkMembers fails like UnmarshalBSON when the coordinates or geometries are missing.
+func (r *geometryReader) checkMembers(g *Geometry) error {
+	switch {
+	case g.Raw != nil:
+		return nil
+	case g.Type == GeometryCollection:
+		if g.Geometries == nil {
+			return &DecodeError{Path: "geometries", Expected: "set of geometries", Actual: bsontype.Null}
+		}
+	case g.Point == nil && g.MultiPoint == nil && g.LineString == nil && g.MultiLineString == nil &&
+		g.Polygon == nil && g.MultiPolygon == nil:
+		return &DecodeError{Path: "coordinates", Expected: "coordinates", Actual: bsontype.Null}
+	}
+
+	return nil
+}
+
+func (r *geometryReader) readBBox(vr bsonrw.ValueReader) ([]float64, error) {
+	if vr.Type() == bsontype.Null {
+		return nil, vr.ReadNull()
+	}
+
+	t := vr.Type()
+	bbox, err := r.readNumbers(vr)
+	if err != nil {
+		return nil, &DecodeError{Expected: "bbox", Actual: t}
+	}
+	if len(bbox) != 4 && len(bbox) != 6 {
+		return nil, &DecodeError{Err: fmt.Errorf("bbox must have 4 or 6 elements, got %d", len(bbox))}
+	}
+
+	return bbox, nil
+}
+
+// readCRS decodes the crs member like UnmarshalBSON does, it is small enough
+// not to bother reading it in place.
+func (r *geometryReader) readCRS(vr bsonrw.ValueReader) (*CRS, error) {
+	if vr.Type() == bsontype.Null {
+		return nil, vr.ReadNull()
+	}
+
+	t, data, err := bsonrw.Copier{}.CopyValueToBytes(vr)
+	if err != nil {
+		return nil, err
+	}
+
+	if t != bsontype.EmbeddedDocument {
+		return nil, &DecodeError{Expected: "crs", Actual: t}
+	}
+
+	var object map[string]interface{}
+	if err = bson.Unmarshal(data, &object); err != nil {
+		return nil, err
+	}
+
+	return decodeCRS(object)
+}
+
+func (r *geometryReader) readPosition(vr bsonrw.ValueReader) (Point, error) {
+	p, err := r.readNumbers(vr)
+	if err == nil && r.opts.StrictPositions && !validPositionLength(p) {
+		return nil, &DecodeError{Err: fmt.Errorf("position must have 2 or 3 elements, got %d", len(p))}
+	}
+
+	return p, err
+}
+
+// readNumbers reads an array of numbers, a position or a bbox.
+func (r *geometryReader) readNumbers(vr bsonrw.ValueReader) ([]float64, error) {
+	if vr.Type() != bsontype.Array {
+		return nil, &DecodeError{Expected: "position", Actual: vr.Type()}
+	}
+
+	ar, err := vr.ReadArray()
+	if err != nil {
+		return nil, err
+	}
+
+	if cap(r.chunk)-len(r.chunk) < 4 {
+		r.chunk = make([]float64, 0, positionChunkSize)
+	}
+	start := len(r.chunk)
+
+	for {
+		evr, err := ar.ReadValue()
+		if err == bsonrw.ErrEOA {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var f float64
+		t := evr.Type()
+		switch {
+		case t == bsontype.Double:
+			f, err = evr.ReadDouble()
+		case t == bsontype.Int32:
+			var i int32
+			i, err = evr.ReadInt32()
+			f = float64(i)
+		case t == bsontype.Int64:
+			var i int64
+			i, err = evr.ReadInt64()
+			f = float64(i)
+		case t == bsontype.Decimal128 && r.opts.Decimal128:
+			var d primitive.Decimal128
+			if d, err = evr.ReadDecimal128(); err == nil {
+				f, err = decimalCoordinate(d)
+			}
+		case t == bsontype.String && r.opts.NumericStrings:
+			var s string
+			if s, err = evr.ReadString(); err == nil {
+				f, err = stringCoordinate(s)
+			}
+		default:
+			return nil, decodeErrorAtIndex(&DecodeError{Expected: "coordinate", Actual: t}, len(r.chunk)-start)
+		}
+		if err != nil {
+			return nil, decodeErrorAtIndex(&DecodeError{Expected: "coordinate", Actual: t, Err: err}, len(r.chunk)-start)
+		}
+
+		// a position longer than the room left in the chunk moves to a new one
+		if len(r.chunk) == cap(r.chunk) {
+			moved := make([]float64, 0, positionChunkSize+len(r.chunk)-start)
+			r.chunk = append(moved, r.chunk[start:]...)
+			start = 0
+		}
+		r.chunk = append(r.chunk, f)
+	}
+
+	end := len(r.chunk)
+	return r.chunk[start:end:end], nil
+}
+
+func (r *geometryReader) readPositions(vr bsonrw.ValueReader) ([]Point, error) {
+	if vr.Type() != bsontype.Array {
+		return nil, &DecodeError{Expected: "set of positions", Actual: vr.Type()}
+	}
+
+	ar, err := vr.ReadArray()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]Point, 0, 4)
+	for {
+		evr, err := ar.ReadValue()
+		if err == bsonrw.ErrEOA {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		p, err := r.readPosition(evr)
+		if err != nil {
+			return nil, decodeErrorAtIndex(err, len(result))
+		}
+		result = append(result, p)
+	}
+
+	return result, nil
+}
+
+func (r *geometryReader) readPaths(vr bsonrw.ValueReader) ([][]Point, error) {
+	if vr.Type() != bsontype.Array {
+		return nil, &DecodeError{Expected: "set of paths", Actual: vr.Type()}
+	}
+
+	ar, err := vr.ReadArray()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][]Point, 0, 1)
+	for {
+		evr, err := ar.ReadValue()
+		if err == bsonrw.ErrEOA {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		path, err := r.readPositions(evr)
+		if err != nil {
+			return nil, decodeErrorAtIndex(err, len(result))
+		}
+		result = append(result, path)
+	}
+
+	return result, nil
+}
+
+func (r *geometryReader) readPolygons(vr bsonrw.ValueReader) ([][][]Point, error) {
+	if vr.Type() != bsontype.Array {
+		return nil, &DecodeError{Expected: "set of polygons", Actual: vr.Type()}
+	}
+
+	ar, err := vr.ReadArray()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([][][]Point, 0, 1)
+	for {
+		evr, err := ar.ReadValue()
+		if err == bsonrw.ErrEOA {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		polygon, err := r.readPaths(evr)
+		if err != nil {
+			return nil, decodeErrorAtIndex(err, len(result))
+		}
+		result = append(result, polygon)
+	}
+
+	return result, nil
+}
+
+func (r *geometryReader) readGeometries(vr bsonrw.ValueReader) ([]*Geometry, error) {
+	if vr.Type() != bsontype.Array {
+		return nil, &DecodeError{Expected: "set of geometries", Actual: vr.Type()}
+	}
+
+	if r.depth++; r.depth > r.opts.maxDepth() {
+		return nil, &DecodeError{Err: fmt.Errorf("%w, more than %d levels", ErrMaxDepth, r.opts.maxDepth())}
+	}
+	defer func() { r.depth-- }()
+
+	ar, err := vr.ReadArray()
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]*Geometry, 0, 2)
+	for {
+		evr, err := ar.ReadValue()
+		if err == bsonrw.ErrEOA {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		g := &Geometry{}
+		if err = r.readGeometry(evr, g); err != nil {
+			return nil, decodeErrorAtIndex(err, len(result))
+		}
+		result = append(result, g)
+	}
+
+	return result, nil
+}
@@ -0,0 +1,230 @@
+package geojson
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// top level geometries go through MarshalBSON and UnmarshalBSON, the codec
+// applies to geometries held in documents.
+type codecDocument struct {
+	Geometry *Geometry `bson:"geometry"`
+}
+
+type codecValueDocument struct {
+	Geometry Geometry `bson:"geometry"`
+}
+
+func codecGeometries() []*Geometry {
+	bbox := NewPoint(Point{1, 2})
+	bbox.BBox = []float64{1, 2, 1, 2}
+
+	return []*Geometry{
+		NewPoint(Point{1, 2}),
+		NewPoint(Point{1, 2, 3}),
+		bbox,
+		NewMultiPoint(Point{1, 2}, Point{3, 4}),
+		NewLineString([]Point{{1, 2}, {3, 4}}),
+		NewMultiLineString([]Point{{1, 2}, {3, 4}}, []Point{{5, 6}, {7, 8}}),
+		NewPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}}),
+		NewMultiPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}}, [][]Point{{{1, 1}, {2, 2}, {3, 1}, {1, 1}}}),
+		NewGeometryCollection(NewPoint(Point{1, 2}), NewLineString([]Point{{1, 2}, {3, 4}})),
+		NewBigPolygon([]Point{{0, 0}, {6, 1}, {3, 6}, {0, 0}}),
+		{Type: GeometryPoint},
+		{Type: GeometryLineString, LineString: []Point{}},
+		{Type: GeometryCollection},
+		{Type: GeometryCollection, Geometries: []*Geometry{}},
+		{Type: "Circle"},
+	}
+}
+
+func TestGeometryCodecEncode(t *testing.T) {
+	registry := NewRegistry()
+
+	for _, g := range codecGeometries() {
+		want, err := bson.Marshal(bson.M{"geometry": g})
+		if err != nil {
+			t.Fatalf("should marshal just fine but got %v", err)
+		}
+
+		for _, v := range []interface{}{codecDocument{g}, codecValueDocument{*g}} {
+			got, err := bson.MarshalWithRegistry(registry, v)
+			if err != nil {
+				t.Fatalf("should encode %T just fine but got %v", v, err)
+			}
+
+			if !bytes.Equal(got, want) {
+				t.Errorf("codec should write the same document as MarshalBSON, got %v, want %v", bson.Raw(got), bson.Raw(want))
+			}
+		}
+	}
+}
+
+func TestGeometryCodecDecode(t *testing.T) {
+	registry := NewRegistry()
+
+	for _, g := range codecGeometries()[:9] {
+		data, _ := bson.Marshal(codecDocument{g})
+
+		got := codecDocument{}
+		if err := bson.UnmarshalWithRegistry(registry, data, &got); err != nil {
+			t.Fatalf("should decode %s just fine but got %v", g.Type, err)
+		}
+
+		want := codecDocument{}
+		_ = bson.Unmarshal(data, &want)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("codec should decode like UnmarshalBSON, got %+v, want %+v", got.Geometry, want.Geometry)
+		}
+	}
+
+	type document struct {
+		Location  *Geometry `bson:"location"`
+		Area      Geometry  `bson:"area"`
+		Missing   *Geometry `bson:"missing"`
+		Unrelated string    `bson:"unrelated"`
+	}
+
+	// the type comes after the coordinates and integers are accepted
+	data, _ := bson.Marshal(bson.D{
+		{Key: "location", Value: bson.D{{Key: "coordinates", Value: bson.A{int32(1), int64(2)}}, {Key: "type", Value: "Point"}}},
+		{Key: "area", Value: NewPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}})},
+		{Key: "missing", Value: nil},
+		{Key: "unrelated", Value: "value"},
+	})
+
+	doc := document{Missing: NewPoint(Point{1, 2})}
+	if err := bson.UnmarshalWithRegistry(registry, data, &doc); err != nil {
+		t.Fatalf("should decode document just fine but got %v", err)
+	}
+
+	if !doc.Location.IsPoint() || !reflect.DeepEqual(doc.Location.Point, Point{1, 2}) {
+		t.Errorf("incorrect location, got %+v", doc.Location)
+	}
+
+	if !doc.Area.IsPolygon() || len(doc.Area.Polygon[0]) != 4 {
+		t.Errorf("incorrect area, got %+v", doc.Area)
+	}
+
+	if doc.Missing != nil || doc.Unrelated != "value" {
+		t.Errorf("incorrect document, got %+v", doc)
+	}
+}
+
+func TestGeometryCodecDecodeInvalid(t *testing.T) {
+	registry := NewRegistry()
+
+	cases := []bson.D{
+		{{Key: "coordinates", Value: bson.A{1, 2}}},
+		{{Key: "type", Value: 1}},
+		{{Key: "type", Value: "Circle"}, {Key: "coordinates", Value: bson.A{1, 2}}},
+		{{Key: "type", Value: "Point"}},
+		{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{"1", 2}}},
+		{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{1, 2}}},
+		{{Key: "type", Value: "Point"}, {Key: "bbox", Value: bson.A{1, 2}}, {Key: "coordinates", Value: bson.A{1, 2}}},
+		{{Key: "type", Value: "GeometryCollection"}, {Key: "geometries", Value: bson.A{1}}},
+	}
+
+	for _, c := range cases {
+		data, _ := bson.Marshal(bson.M{"geometry": c})
+		if err := bson.UnmarshalWithRegistry(registry, data, &codecDocument{}); err == nil {
+			t.Errorf("should fail to decode %v", c)
+		}
+	}
+}
+
+func TestGeometryCodecLenient(t *testing.T) {
+	registry := RegisterGeometryCodec(bson.NewRegistryBuilder()).
+		RegisterTypeDecoder(tGeometryPtr, NewGeometryCodec(DecodeOptions{Lenient: true})).
+		Build()
+
+	data, _ := bson.Marshal(bson.M{"geometry": bson.D{
+		{Key: "type", Value: "GeometryCollection"},
+		{Key: "geometries", Value: bson.A{
+			bson.D{{Key: "type", Value: "point"}, {Key: "coordinates", Value: bson.A{1, 2}}},
+			bson.D{{Key: "type", Value: "Circle"}, {Key: "radius", Value: 10}},
+		}},
+	}})
+
+	doc := codecDocument{}
+	if err := bson.UnmarshalWithRegistry(registry, data, &doc); err != nil {
+		t.Fatalf("should decode leniently just fine but got %v", err)
+	}
+
+	g := doc.Geometry
+	if len(g.Geometries) != 2 || !g.Geometries[0].IsPoint() || g.Geometries[1].Raw == nil {
+		t.Fatalf("incorrect geometry, got %+v", g)
+	}
+
+	encoded, err := bson.MarshalWithRegistry(registry, codecDocument{g.Geometries[1]})
+	if err != nil {
+		t.Fatalf("should encode raw geometry just fine but got %v", err)
+	}
+
+	if v := bson.Raw(encoded).Lookup("geometry", "radius").Int32(); v != 10 {
+		t.Errorf("raw geometry should round trip, got %v", bson.Raw(encoded))
+	}
+}
+
+func benchmarkPolygon() *Geometry {
+	ring := make([]Point, 0, 1001)
+	for i := 0; i < 1000; i++ {
+		ring = append(ring, Point{float64(i%360) - 180, float64(i%180) - 90})
+	}
+	ring = append(ring, ring[0])
+
+	return NewPolygon([][]Point{ring})
+}
+
+func BenchmarkMarshalBSON(b *testing.B) {
+	g := codecDocument{benchmarkPolygon()}
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := bson.Marshal(g); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGeometryCodecEncode(b *testing.B) {
+	g := codecDocument{benchmarkPolygon()}
+	registry := NewRegistry()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := bson.MarshalWithRegistry(registry, g); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkUnmarshalBSON(b *testing.B) {
+	data, _ := bson.Marshal(codecDocument{benchmarkPolygon()})
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := bson.Unmarshal(data, &codecDocument{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkGeometryCodecDecode(b *testing.B) {
+	data, _ := bson.Marshal(codecDocument{benchmarkPolygon()})
+	registry := NewRegistry()
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if err := bson.UnmarshalWithRegistry(registry, data, &codecDocument{}); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
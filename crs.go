@@ -0,0 +1,92 @@
+package geojson
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// StrictWindingCRS is the name of the custom MongoDB coordinate reference system
+// of big polygons: single-ringed polygons whose interior is on the left of the
+// ring, walking it in order, so they may cover more than a hemisphere.
+// It is only supported by $geoWithin and $geoIntersects queries.
+// https://docs.mongodb.com/v4.2/reference/operator/query/geometry/#big-polygon
+const StrictWindingCRS = "urn:x-mongodb:crs:strictwinding:EPSG:4326"
+
+// A CRS is the crs member of a geometry, a named coordinate reference system.
+//
+//	crs: { type: "name", properties: { name: "urn:x-mongodb:crs:strictwinding:EPSG:4326" } }
+type CRS struct {
+	Type       string        `bson:"type" json:"type"`
+	Properties CRSProperties `bson:"properties" json:"properties"`
+}
+
+// CRSProperties holds the name of a named CRS.
+type CRSProperties struct {
+	Name string `bson:"name" json:"name"`
+}
+
+// NewNamedCRS creates a CRS of type name.
+func NewNamedCRS(name string) *CRS {
+	return &CRS{Type: "name", Properties: CRSProperties{Name: name}}
+}
+
+// IsStrictWinding returns true if the CRS is StrictWindingCRS, false for a nil CRS.
+func (c *CRS) IsStrictWinding() bool {
+	return c != nil && c.Properties.Name == StrictWindingCRS
+}
+
+// NewBigPolygon creates a polygon with the strict winding CRS, its interior is
+// the region on the left of the ring, e.g. a counter-clockwise ring around the
+// equator encloses the northern hemisphere.
+// https://docs.mongodb.com/v4.2/reference/operator/query/geometry/#big-polygon
+func NewBigPolygon(ring []Point) *Geometry {
+	return &Geometry{
+		Type:    GeometryPolygon,
+		Polygon: [][]Point{ring},
+		CRS:     NewNamedCRS(StrictWindingCRS),
+	}
+}
+
+func decodeCRS(data interface{}) (*CRS, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	object, ok := documentMap(data)
+	if !ok || object["type"] != "name" {
+		return nil, decodeError("crs", data)
+	}
+
+	properties, ok := documentMap(object["properties"])
+	if !ok {
+		return nil, decodeError("crs", data)
+	}
+
+	name, ok := properties["name"].(string)
+	if !ok {
+		return nil, decodeError("crs", data)
+	}
+
+	return NewNamedCRS(name), nil
+}
+
+// documentMap returns the embedded document v as a map, whichever type
+// bson.Unmarshal or json.Unmarshal decoded it to.
+func documentMap(v interface{}) (map[string]interface{}, bool) {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		return v, true
+	case primitive.M:
+		return v, true
+	case primitive.D:
+		return v.Map(), true
+	case bson.Raw:
+		var object map[string]interface{}
+		if err := bson.Unmarshal(v, &object); err != nil {
+			return nil, false
+		}
+		return object, true
+	}
+
+	return nil, false
+}
@@ -0,0 +1,92 @@
+package geojson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestCRSRoundTrip(t *testing.T) {
+	g := NewBigPolygon([]Point{{-10, -10}, {10, -10}, {10, 10}, {-10, 10}, {-10, -10}})
+
+	blob, err := bson.Marshal(g)
+	if err != nil {
+		t.Fatalf("should marshal to bson just fine but got %v", err)
+	}
+
+	got, err := UnmarshalGeometry(blob)
+	if err != nil {
+		t.Fatalf("should unmarshal geometry just fine but got %v", err)
+	}
+	if !reflect.DeepEqual(got, g) || !got.CRS.IsStrictWinding() {
+		t.Errorf("incorrect geometry, got %+v", got)
+	}
+
+	data, err := got.MarshalJSON()
+	if err != nil {
+		t.Fatalf("should marshal to json just fine but got %v", err)
+	}
+
+	got, err = UnmarshalGeometryRawJSON(data)
+	if err != nil || !reflect.DeepEqual(got, g) {
+		t.Errorf("incorrect json round trip, got %+v, %v", got, err)
+	}
+
+	if NewPolygon(g.Polygon).CRS.IsStrictWinding() {
+		t.Errorf("should not have a strict winding CRS")
+	}
+}
+
+func TestCRSInvalid(t *testing.T) {
+	cases := []interface{}{
+		"EPSG:4326",
+		bson.M{"type": "link", "properties": bson.M{"href": "http://example.com/crs/42"}},
+		bson.M{"type": "name"},
+		bson.M{"type": "name", "properties": bson.M{"name": 4326}},
+	}
+
+	for _, crs := range cases {
+		geometry := bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1, 2}}, {Key: "crs", Value: crs}}
+		blob, _ := bson.Marshal(geometry)
+		if _, err := UnmarshalGeometry(blob); err == nil {
+			t.Errorf("should fail on crs %v", crs)
+		}
+
+		data, _ := bson.Marshal(bson.M{"geometry": geometry})
+		if err := bson.UnmarshalWithRegistry(NewRegistry(), data, &codecDocument{}); err == nil {
+			t.Errorf("codec should fail on crs %v", crs)
+		}
+	}
+}
+
+func TestBigPolygon(t *testing.T) {
+	// counter-clockwise around the north pole, the big polygon is the region
+	// north of latitude -10, its clockwise twin is a cap around the south pole
+	ring := []Point{{0, -10}, {90, -10}, {180, -10}, {-90, -10}, {0, -10}}
+	big := NewBigPolygon(ring)
+	small := NewPolygon([][]Point{ring})
+
+	north, south := NewPoint(Point{0, 45}), NewPoint(Point{0, -80})
+	if !big.Contains(north) || big.Contains(south) {
+		t.Errorf("big polygon should contain the north only")
+	}
+	if small.Contains(north) || !small.Contains(south) {
+		t.Errorf("polygon should contain the south only")
+	}
+
+	earth := Spherical.earthArea()
+	if a := Spherical.Area(big) + Spherical.Area(small); math.Abs(a-earth) > 1e-6*earth {
+		t.Errorf("incorrect areas, got %v, want %v", a, earth)
+	}
+	if Spherical.Area(big) < earth/2 {
+		t.Errorf("big polygon should cover more than a hemisphere, got %v", Spherical.Area(big))
+	}
+
+	// the winding order of a big polygon is left alone
+	big.Rewind(false)
+	if !reflect.DeepEqual(big.Polygon[0], ring) {
+		t.Errorf("should not rewind a big polygon, got %v", big.Polygon[0])
+	}
+}
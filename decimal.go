@@ -0,0 +1,94 @@
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+var errNotFinite = errors.New("not a finite number")
+
+// decimalCoordinate converts a Decimal128 coordinate to a float64, it fails if
+// the value is beyond the range of a float64 or if it has more significant
+// digits than a float64 holds, e.g. 0.30000000000000001.
+func decimalCoordinate(d primitive.Decimal128) (float64, error) {
+	if d.IsNaN() || d.IsInf() != 0 {
+		return 0, errNotFinite
+	}
+
+	f, err := strconv.ParseFloat(d.String(), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s is out of the range of a float64", d)
+	}
+
+	back, err := decimalFromCoordinate(f)
+	if err != nil || !equalDecimals(d, back) {
+		return 0, fmt.Errorf("%s loses precision as a float64", d)
+	}
+
+	return f, nil
+}
+
+// stringCoordinate converts a coordinate stored as a string holding a decimal number.
+func stringCoordinate(s string) (float64, error) {
+	f, err := strconv.ParseFloat(strings.TrimSpace(s), 64)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a number", s)
+	}
+
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0, errNotFinite
+	}
+
+	return f, nil
+}
+
+// decimalFromCoordinate converts a coordinate to the Decimal128 with the fewest
+// digits that converts back to the same float64.
+func decimalFromCoordinate(f float64) (primitive.Decimal128, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return primitive.Decimal128{}, errNotFinite
+	}
+
+	return primitive.ParseDecimal128(strconv.FormatFloat(f, 'e', -1, 64))
+}
+
+// equalDecimals returns true if the finite decimals have the same value,
+// regardless of their trailing zeros, e.g. 1.50 and 1.5.
+func equalDecimals(a, b primitive.Decimal128) bool {
+	ai, aexp, err := a.BigInt()
+	if err != nil {
+		return false
+	}
+	bi, bexp, err := b.BigInt()
+	if err != nil {
+		return false
+	}
+
+	ai, aexp = trimDecimal(ai, aexp)
+	bi, bexp = trimDecimal(bi, bexp)
+	return aexp == bexp && ai.Cmp(bi) == 0
+}
+
+// trimDecimal removes the trailing zeros of the coefficient of a decimal.
+func trimDecimal(coefficient *big.Int, exp int) (*big.Int, int) {
+	if coefficient.Sign() == 0 {
+		return coefficient, 0
+	}
+
+	ten := big.NewInt(10)
+	q, r := new(big.Int), new(big.Int)
+	for {
+		q.QuoRem(coefficient, ten, r)
+		if r.Sign() != 0 {
+			return coefficient, exp
+		}
+		coefficient, q = q, coefficient
+		exp++
+	}
+}
@@ -0,0 +1,205 @@
+package geojson
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+func mustDecimal(s string) primitive.Decimal128 {
+	d, err := primitive.ParseDecimal128(s)
+	if err != nil {
+		panic(err)
+	}
+	return d
+}
+
+func TestDecimalCoordinate(t *testing.T) {
+	cases := []struct {
+		decimal string
+		want    float64
+		valid   bool
+	}{
+		{"48.85", 48.85, true},
+		{"-2.350", -2.35, true},
+		{"1.5E+3", 1500, true},
+		{"0", 0, true},
+		{"0.1", 0.1, true},
+		{"0.30000000000000001", 0, false},
+		{"1.23456789012345678901234567890", 0, false},
+		{"1E+400", 0, false},
+		{"1E-400", 0, false},
+		{"NaN", 0, false},
+		{"-Infinity", 0, false},
+	}
+
+	for _, c := range cases {
+		f, err := decimalCoordinate(mustDecimal(c.decimal))
+		if (err == nil) != c.valid {
+			t.Errorf("incorrect conversion of %s, got %v", c.decimal, err)
+		}
+		if err == nil && f != c.want {
+			t.Errorf("incorrect value of %s, got %v", c.decimal, f)
+		}
+	}
+}
+
+func TestStringCoordinate(t *testing.T) {
+	if f, err := stringCoordinate(" -73.98 "); err != nil || f != -73.98 {
+		t.Errorf("incorrect conversion, got %v, %v", f, err)
+	}
+
+	for _, s := range []string{"", "abc", "1,5", "NaN", "Inf", "1e400"} {
+		if _, err := stringCoordinate(s); err == nil {
+			t.Errorf("should fail to convert %q", s)
+		}
+	}
+}
+
+func TestDecodeDecimalCoordinates(t *testing.T) {
+	geometry := bson.D{
+		{Key: "type", Value: "LineString"},
+		{Key: "bbox", Value: bson.A{mustDecimal("1.5"), "2", 3, 4}},
+		{Key: "coordinates", Value: bson.A{
+			bson.A{mustDecimal("1.5"), mustDecimal("2.25")},
+			bson.A{"3", " 4.5"},
+		}},
+	}
+	blob, _ := bson.Marshal(geometry)
+	data, _ := bson.Marshal(bson.M{"geometry": geometry})
+
+	opts := DecodeOptions{Decimal128: true, NumericStrings: true}
+	registry := RegisterGeometryCodec(bson.NewRegistryBuilder()).
+		RegisterTypeDecoder(tGeometryPtr, NewGeometryCodec(opts)).
+		Build()
+
+	g, err := UnmarshalGeometryWithOptions(blob, opts)
+	if err != nil {
+		t.Fatalf("should decode just fine but got %v", err)
+	}
+
+	doc := codecDocument{}
+	if err = bson.UnmarshalWithRegistry(registry, data, &doc); err != nil {
+		t.Fatalf("should decode with the codec just fine but got %v", err)
+	}
+
+	expected := []Point{{1.5, 2.25}, {3, 4.5}}
+	for _, g := range []*Geometry{g, doc.Geometry} {
+		for i, p := range g.LineString {
+			if !p.Equal(expected[i], 0) {
+				t.Errorf("incorrect position %d, got %v", i, p)
+			}
+		}
+		if len(g.BBox) != 4 || g.BBox[0] != 1.5 || g.BBox[1] != 2 {
+			t.Errorf("incorrect bbox, got %v", g.BBox)
+		}
+	}
+
+	for _, opts := range []DecodeOptions{{}, {Decimal128: true}, {NumericStrings: true}} {
+		_, err := UnmarshalGeometryWithOptions(blob, opts)
+		var e *DecodeError
+		if !errors.As(err, &e) {
+			t.Fatalf("should fail without both options, %+v, but got %v", opts, err)
+		}
+	}
+
+	imprecise, _ := bson.Marshal(bson.D{
+		{Key: "type", Value: "Point"},
+		{Key: "coordinates", Value: bson.A{1, mustDecimal("0.30000000000000001")}},
+	})
+	_, err = UnmarshalGeometryWithOptions(imprecise, opts)
+	var e *DecodeError
+	if !errors.As(err, &e) || e.Path != "coordinates[1]" || e.Actual != bsontype.Decimal128 || e.Err == nil {
+		t.Errorf("should fail to decode an imprecise decimal, got %v", err)
+	}
+}
+
+func TestDecodeDecimalFeatureBBox(t *testing.T) {
+	opts := DecodeOptions{Decimal128: true, NumericStrings: true}
+	bbox := bson.A{mustDecimal("1.5"), "2", 3, 4}
+	feature := bson.D{
+		{Key: "type", Value: "Feature"},
+		{Key: "bbox", Value: bbox},
+		{Key: "geometry", Value: bson.D{{Key: "type", Value: "Point"}, {Key: "bbox", Value: bbox}, {Key: "coordinates", Value: bson.A{1, 2}}}},
+		{Key: "properties", Value: bson.D{}},
+	}
+
+	data, _ := bson.Marshal(feature)
+	f, err := UnmarshalFeatureWithOptions(data, opts)
+	if err != nil {
+		t.Fatalf("should decode just fine but got %v", err)
+	}
+	if len(f.BBox) != 4 || f.BBox[0] != 1.5 || f.BBox[1] != 2 || f.Geometry.BBox[0] != 1.5 {
+		t.Errorf("incorrect bbox, got %v", f.BBox)
+	}
+
+	data, _ = bson.Marshal(bson.D{{Key: "type", Value: "FeatureCollection"}, {Key: "bbox", Value: bbox}, {Key: "features", Value: bson.A{feature}}})
+	fc, err := UnmarshalFeatureCollectionWithOptions(data, opts)
+	if err != nil {
+		t.Fatalf("should decode just fine but got %v", err)
+	}
+	if len(fc.BBox) != 4 || fc.BBox[0] != 1.5 || fc.Features[0].BBox[1] != 2 {
+		t.Errorf("incorrect bbox, got %v", fc.BBox)
+	}
+
+	data, _ = bson.Marshal(bson.D{
+		{Key: "type", Value: "Feature"},
+		{Key: "bbox", Value: bson.A{1, mustDecimal("0.30000000000000001"), 3, 4}},
+		{Key: "geometry", Value: nil},
+	})
+	_, err = UnmarshalFeatureWithOptions(data, opts)
+	var e *DecodeError
+	if !errors.As(err, &e) || e.Path != "bbox[1]" || e.Actual != bsontype.Decimal128 || e.Err == nil {
+		t.Errorf("should fail to decode an imprecise decimal bbox, got %v", err)
+	}
+}
+
+func TestEncodeDecimalCoordinates(t *testing.T) {
+	g := NewLineString([]Point{{48.85, 2.35}, {-73.98, 40.75, 10}})
+	g.BBox = []float64{-73.98, 2.35, 48.85, 40.75}
+
+	blob, err := MarshalGeometryWithOptions(g, EncodeOptions{Decimal128: true})
+	if err != nil {
+		t.Fatalf("should encode just fine but got %v", err)
+	}
+
+	c := bson.Raw(blob).Lookup("coordinates", "0", "0")
+	if d, ok := c.Decimal128OK(); !ok || d.String() != "48.85" {
+		t.Errorf("should write coordinates as Decimal128, got %v", c)
+	}
+
+	if _, err := UnmarshalGeometry(blob); err == nil {
+		t.Errorf("should fail to decode decimals without the option")
+	}
+
+	decoded, err := UnmarshalGeometryWithOptions(blob, DecodeOptions{Decimal128: true})
+	if err != nil {
+		t.Fatalf("should decode just fine but got %v", err)
+	}
+	if !decoded.LineString[1].Equal(g.LineString[1], 0) || decoded.BBox[0] != -73.98 {
+		t.Errorf("should decode back the same geometry, got %+v", decoded)
+	}
+
+	plain, _ := MarshalGeometryWithOptions(g, EncodeOptions{})
+	if expected, _ := bson.Marshal(g); !bson.Raw(plain).Lookup("coordinates").Equal(bson.Raw(expected).Lookup("coordinates")) {
+		t.Errorf("should write doubles like MarshalBSON without the option")
+	}
+
+	codec := &GeometryCodec{EncodeOptions: EncodeOptions{Decimal128: true}}
+	registry := bson.NewRegistryBuilder().RegisterTypeEncoder(tGeometryPtr, codec).Build()
+	data, err := bson.MarshalWithRegistry(registry, codecDocument{g})
+	if err != nil {
+		t.Fatalf("should encode with the codec just fine but got %v", err)
+	}
+	if c := bson.Raw(data).Lookup("geometry", "coordinates", "1", "2"); c.Type != bsontype.Decimal128 {
+		t.Errorf("should write coordinates as Decimal128 with the codec, got %v", c)
+	}
+
+	if _, err := MarshalGeometryWithOptions(NewPoint(Point{1, math.Inf(1)}), EncodeOptions{Decimal128: true}); err == nil {
+		t.Errorf("should fail to encode an infinite coordinate as a Decimal128")
+	}
+}
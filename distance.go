@@ -0,0 +1,149 @@
+package geojson
+
+import (
+	"math"
+)
+
+// Meters is a distance in meters, the unit of the distances MongoDB returns
+// from $geoNear and $near with GeoJSON points and a 2dsphere index.
+type Meters float64
+
+// MetersFromRadians converts an angle on the sphere of EarthRadius to meters,
+// e.g. a distance returned by $geoNear with legacy coordinate pairs.
+func MetersFromRadians(radians float64) Meters {
+	return Meters(radians * EarthRadius)
+}
+
+// Kilometers returns the distance in kilometers.
+func (m Meters) Kilometers() float64 {
+	return float64(m) / 1000
+}
+
+// Radians returns the distance as an angle on the sphere of EarthRadius,
+// e.g. the radius of $centerSphere.
+func (m Meters) Radians() float64 {
+	return float64(m) / EarthRadius
+}
+
+// Distance returns the great circle distance between the positions with the
+// haversine formula on a sphere of EarthRadius, like MongoDB computes spherical
+// distances. Use Geodesic.Distance for the distance on the WGS84 ellipsoid.
+func Distance(a, b Point) Meters {
+	return Spherical.Distance(a, b)
+}
+
+// InitialBearing returns the bearing from a towards b along the great circle,
+// in degrees clockwise from north in [0, 360).
+func InitialBearing(a, b Point) float64 {
+	return Spherical.InitialBearing(a, b)
+}
+
+// FinalBearing returns the bearing when arriving at b from a along the great
+// circle, in degrees clockwise from north in [0, 360).
+func FinalBearing(a, b Point) float64 {
+	return Spherical.FinalBearing(a, b)
+}
+
+// Distance returns the shortest distance between the positions.
+// Geodesic solves the inverse problem on the WGS84 ellipsoid, which Vincenty's
+// formulae also solve, with Karney's algorithm that converges for nearly
+// antipodal positions as well. Spherical uses the haversine formula.
+// Positions without a longitude and a latitude are 0 meters apart.
+func (m Measure) Distance(a, b Point) Meters {
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+
+	if m == Spherical {
+		return Meters(haversine(a, b))
+	}
+
+	s12, _, _, _ := wgs84.inverse(a[1], a[0], b[1], b[0], false)
+	return Meters(s12)
+}
+
+// InitialBearing returns the bearing from a towards b, see InitialBearing.
+func (m Measure) InitialBearing(a, b Point) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+
+	if m == Spherical {
+		return sphericalBearing(a, b)
+	}
+
+	_, azi1, _, _ := wgs84.inverse(a[1], a[0], b[1], b[0], false)
+	return bearing(azi1)
+}
+
+// FinalBearing returns the bearing when arriving at b from a, see FinalBearing.
+func (m Measure) FinalBearing(a, b Point) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 0
+	}
+
+	if m == Spherical {
+		return bearing(sphericalBearing(b, a) + 180)
+	}
+
+	_, _, azi2, _ := wgs84.inverse(a[1], a[0], b[1], b[0], false)
+	return bearing(azi2)
+}
+
+// Destination returns the position reached from p after the distance along the
+// great circle with the initial bearing, in degrees clockwise from north, on a
+// sphere of EarthRadius. The altitude of p is kept.
+func Destination(p Point, bearing float64, distance Meters) Point {
+	if len(p) < 2 {
+		return nil
+	}
+
+	lat1, lon1 := p[1]*degree, p[0]*degree
+	theta, delta := bearing*degree, distance.Radians()
+
+	sinLat := math.Sin(lat1)*math.Cos(delta) + math.Cos(lat1)*math.Sin(delta)*math.Cos(theta)
+	lat2 := math.Asin(math.Max(-1, math.Min(1, sinLat)))
+	lon2 := lon1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(lat1), math.Cos(delta)-math.Sin(lat1)*sinLat)
+
+	q := Point{normalizeLongitude(lon2 / degree), lat2 / degree}
+	return append(q, p[2:]...)
+}
+
+// Midpoint returns the position halfway between a and b along the great circle.
+func Midpoint(a, b Point) Point {
+	if len(a) < 2 || len(b) < 2 {
+		return nil
+	}
+
+	lat1, lon1, lat2 := a[1]*degree, a[0]*degree, b[1]*degree
+	dlon := angDiff(a[0], b[0], nil) * degree
+
+	bx, by := math.Cos(lat2)*math.Cos(dlon), math.Cos(lat2)*math.Sin(dlon)
+	lat := math.Atan2(math.Sin(lat1)+math.Sin(lat2), math.Hypot(math.Cos(lat1)+bx, by))
+	lon := lon1 + math.Atan2(by, math.Cos(lat1)+bx)
+
+	return Point{normalizeLongitude(lon / degree), lat / degree}
+}
+
+// sphericalBearing returns the initial bearing from a to b on the sphere.
+func sphericalBearing(a, b Point) float64 {
+	lat1, lat2 := a[1]*degree, b[1]*degree
+	dlon := angDiff(a[0], b[0], nil) * degree
+
+	y := math.Sin(dlon) * math.Cos(lat2)
+	x := math.Cos(lat1)*math.Sin(lat2) - math.Sin(lat1)*math.Cos(lat2)*math.Cos(dlon)
+	return bearing(math.Atan2(y, x) / degree)
+}
+
+// bearing normalizes an angle in degrees to [0, 360).
+func bearing(angle float64) float64 {
+	angle = math.Mod(angle, 360)
+	if angle < 0 {
+		angle += 360
+	}
+	if angle == 360 {
+		return 0
+	}
+
+	return angle
+}
@@ -0,0 +1,94 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDistance(t *testing.T) {
+	jfk, lhr := Point{-73.8, 40.6}, Point{-0.5, 51.6}
+
+	if d := Distance(jfk, lhr); math.Abs(float64(d)-5543054.780) > 1e-3 {
+		t.Errorf("incorrect spherical distance, got %v", d)
+	}
+
+	if d := Geodesic.Distance(jfk, lhr); math.Abs(float64(d)-5551759.400) > 1e-3 {
+		t.Errorf("incorrect geodesic distance, got %v", d)
+	}
+
+	if d := Distance(Point{179.5, 0}, Point{-179.5, 0}); math.Abs(d.Radians()-degree) > 1e-12 {
+		t.Errorf("incorrect distance across the antimeridian, got %v", d)
+	}
+
+	// nearly antipodal positions
+	if d := Geodesic.Distance(Point{0, 0}, Point{179.5, 0.5}); math.Abs(float64(d)-19936288.579) > 1e-3 {
+		t.Errorf("incorrect antipodal distance, got %v", d)
+	}
+
+	if d := Distance(jfk, Point{1}); d != 0 {
+		t.Errorf("should be 0 for an invalid position, got %v", d)
+	}
+
+	if d := MetersFromRadians(0.5); d.Radians() != 0.5 || d.Kilometers() != 0.5*EarthRadius/1000 {
+		t.Errorf("incorrect conversions, got %v", d)
+	}
+}
+
+func TestBearing(t *testing.T) {
+	jfk, lhr := Point{-73.8, 40.6}, Point{-0.5, 51.6}
+
+	cases := []struct {
+		name string
+		got  float64
+		want float64
+	}{
+		{"initial", InitialBearing(jfk, lhr), 51.169272672},
+		{"final", FinalBearing(jfk, lhr), 107.781686005},
+		{"geodesic initial", Geodesic.InitialBearing(jfk, lhr), 51.198882846},
+		{"geodesic final", Geodesic.FinalBearing(jfk, lhr), 107.821776736},
+		{"north", InitialBearing(Point{0, 0}, Point{0, 10}), 0},
+		{"west", InitialBearing(Point{0, 0}, Point{-10, 0}), 270},
+		{"south across the antimeridian", InitialBearing(Point{179, 10}, Point{-179, -10}), 174.259925864},
+	}
+
+	for _, c := range cases {
+		if math.Abs(c.got-c.want) > 1e-3 {
+			t.Errorf("incorrect %s bearing, got %v, want %v", c.name, c.got, c.want)
+		}
+	}
+}
+
+func TestDestination(t *testing.T) {
+	jfk, lhr := Point{-73.8, 40.6}, Point{-0.5, 51.6}
+
+	p := Destination(jfk, InitialBearing(jfk, lhr), Distance(jfk, lhr))
+	if !p.Equal(lhr, 1e-9) {
+		t.Errorf("incorrect destination, got %v", p)
+	}
+
+	p = Destination(Point{179, 0, 12}, 90, MetersFromRadians(2*degree))
+	if !p.Equal(Point{-179, 0, 12}, 1e-9) {
+		t.Errorf("incorrect destination across the antimeridian, got %v", p)
+	}
+
+	if p := Destination(Point{}, 0, 1); p != nil {
+		t.Errorf("should be nil for an invalid position, got %v", p)
+	}
+}
+
+func TestMidpoint(t *testing.T) {
+	jfk, lhr := Point{-73.8, 40.6}, Point{-0.5, 51.6}
+
+	m := Midpoint(jfk, lhr)
+	if !m.Equal(Point{-41.407589494, 52.252818434}, 1e-9) {
+		t.Errorf("incorrect midpoint, got %v", m)
+	}
+
+	if a, b := Distance(jfk, m), Distance(m, lhr); math.Abs(float64(a-b)) > 1e-6 {
+		t.Errorf("should be halfway, got %v and %v", a, b)
+	}
+
+	if m := Midpoint(Point{179, 0}, Point{-179, 0}); !m.Equal(Point{180, 0}, 1e-9) && !m.Equal(Point{-180, 0}, 1e-9) {
+		t.Errorf("incorrect midpoint across the antimeridian, got %v", m)
+	}
+}
@@ -0,0 +1,114 @@
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// ErrMaxDepth is wrapped by the DecodeError of a GeometryCollection nested more
+// deeply than DecodeOptions.MaxDepth.
+var ErrMaxDepth = errors.New("geometry collections nested too deeply")
+
+// DecodeError describes why a geometry, feature or feature collection could not
+// be decoded and where, it can be retrieved with errors.As.
+// Path locates the offending value from the decoded object, for example
+// geometries[2].coordinates[0][5][1], it is empty for the object itself.
+// Expected is the kind of value that was expected, for example "coordinate",
+// and Actual is the BSON type of the value found, Null when it is missing.
+// Err is set instead when the value has the expected type but is rejected,
+// e.g. an unknown geometry type, or when the document could not be read.
+type DecodeError struct {
+	Path     string
+	Expected string
+	Actual   bsontype.Type
+	Err      error
+}
+
+func (e *DecodeError) Error() string {
+	msg := fmt.Sprintf("not a valid %s, got %s", e.Expected, e.Actual)
+	if e.Err != nil {
+		msg = e.Err.Error()
+	}
+
+	if e.Path == "" {
+		return msg
+	}
+	return fmt.Sprintf("%s: %s", e.Path, msg)
+}
+
+// Unwrap returns the underlying failure, if any.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decodeError reports that data, as decoded by bson.Unmarshal or json.Unmarshal,
+// is not the expected kind of value.
+func decodeError(expected string, data interface{}) error {
+	return &DecodeError{Expected: expected, Actual: bsonTypeOf(data)}
+}
+
+// decodeErrorAt prefixes the path of err with a member name or an array index,
+// any other error becomes the Err of a DecodeError located there.
+func decodeErrorAt(err error, member string) error {
+	if err == nil {
+		return nil
+	}
+
+	e, ok := err.(*DecodeError)
+	if !ok {
+		return &DecodeError{Path: member, Err: err}
+	}
+
+	switch {
+	case e.Path == "":
+		e.Path = member
+	case e.Path[0] == '[':
+		e.Path = member + e.Path
+	default:
+		e.Path = member + "." + e.Path
+	}
+
+	return e
+}
+
+// decodeErrorAtIndex prefixes the path of err with an array index.
+func decodeErrorAtIndex(err error, i int) error {
+	return decodeErrorAt(err, "["+strconv.Itoa(i)+"]")
+}
+
+// bsonTypeOf returns the BSON type a decoded value comes from.
+func bsonTypeOf(v interface{}) bsontype.Type {
+	switch v.(type) {
+	case nil:
+		return bsontype.Null
+	case float64:
+		return bsontype.Double
+	case int32:
+		return bsontype.Int32
+	case int64, int:
+		return bsontype.Int64
+	case string:
+		return bsontype.String
+	case bool:
+		return bsontype.Boolean
+	case primitive.A, []interface{}:
+		return bsontype.Array
+	case map[string]interface{}, primitive.M, primitive.D, bson.Raw:
+		return bsontype.EmbeddedDocument
+	case primitive.Decimal128:
+		return bsontype.Decimal128
+	case primitive.ObjectID:
+		return bsontype.ObjectID
+	case primitive.DateTime:
+		return bsontype.DateTime
+	case primitive.Binary:
+		return bsontype.Binary
+	}
+
+	return bsontype.Undefined
+}
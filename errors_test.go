@@ -0,0 +1,160 @@
+package geojson
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// badCollection has a string as the second coordinate of the sixth position
+// of the polygon of its third member.
+var badCollection = bson.D{
+	{Key: "type", Value: "GeometryCollection"},
+	{Key: "geometries", Value: bson.A{
+		bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1, 2}}},
+		bson.D{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{bson.A{1, 2}, bson.A{3, 4}}}},
+		bson.D{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: bson.A{bson.A{
+			bson.A{0, 0}, bson.A{1, 0}, bson.A{2, 0}, bson.A{2, 1}, bson.A{1, 1}, bson.A{0, "1"}, bson.A{0, 0},
+		}}}},
+	}},
+}
+
+func TestDecodeErrorPath(t *testing.T) {
+	blob, err := bson.Marshal(badCollection)
+	if err != nil {
+		t.Fatalf("should marshal just fine but got %v", err)
+	}
+
+	ext, err := bson.MarshalExtJSON(badCollection, false, false)
+	if err != nil {
+		t.Fatalf("should marshal just fine but got %v", err)
+	}
+
+	data, _ := bson.Marshal(bson.M{"geometry": badCollection})
+
+	decoders := map[string]func() error{
+		"bson": func() error {
+			_, err := UnmarshalGeometry(blob)
+			return err
+		},
+		"json": func() error {
+			_, err := UnmarshalGeometryRawJSON(ext)
+			return err
+		},
+		"codec": func() error {
+			return bson.UnmarshalWithRegistry(NewRegistry(), data, &codecDocument{})
+		},
+		"raw": func() error {
+			_, err := RawGeometry(blob).NumPositions()
+			return err
+		},
+	}
+
+	for name, decode := range decoders {
+		var e *DecodeError
+		if err := decode(); !errors.As(err, &e) {
+			t.Fatalf("%s: should fail with a DecodeError but got %v", name, err)
+		}
+
+		if e.Path != "geometries[2].coordinates[0][5][1]" {
+			t.Errorf("%s: incorrect path, got %q", name, e.Path)
+		}
+		if e.Expected != "coordinate" || e.Actual != bsontype.String {
+			t.Errorf("%s: incorrect expected and actual types, got %q and %s", name, e.Expected, e.Actual)
+		}
+	}
+}
+
+func TestDecodeErrorMembers(t *testing.T) {
+	cases := []struct {
+		name     string
+		geometry bson.D
+		path     string
+		actual   bsontype.Type
+	}{
+		{
+			name:     "missing type",
+			geometry: bson.D{{Key: "coordinates", Value: bson.A{1, 2}}},
+			path:     "type",
+			actual:   bsontype.Null,
+		},
+		{
+			name:     "type not a string",
+			geometry: bson.D{{Key: "type", Value: 1}, {Key: "coordinates", Value: bson.A{1, 2}}},
+			path:     "type",
+			actual:   bsontype.Int32,
+		},
+		{
+			name:     "position not an array",
+			geometry: bson.D{{Key: "type", Value: "MultiPoint"}, {Key: "coordinates", Value: bson.A{bson.A{1, 2}, true}}},
+			path:     "coordinates[1]",
+			actual:   bsontype.Boolean,
+		},
+		{
+			name:     "bbox not an array",
+			geometry: bson.D{{Key: "type", Value: "Point"}, {Key: "bbox", Value: "box"}, {Key: "coordinates", Value: bson.A{1, 2}}},
+			path:     "bbox",
+			actual:   bsontype.String,
+		},
+		{
+			name:     "missing member",
+			geometry: bson.D{{Key: "type", Value: "GeometryCollection"}, {Key: "geometries", Value: bson.A{bson.D{{Key: "type", Value: "Point"}}}}},
+			path:     "geometries[0].coordinates",
+			actual:   bsontype.Null,
+		},
+	}
+
+	for _, c := range cases {
+		blob, _ := bson.Marshal(c.geometry)
+		data, _ := bson.Marshal(bson.M{"geometry": c.geometry})
+
+		errs := map[string]error{}
+		_, errs["bson"] = UnmarshalGeometry(blob)
+		errs["codec"] = bson.UnmarshalWithRegistry(NewRegistry(), data, &codecDocument{})
+
+		for decoder, err := range errs {
+			var e *DecodeError
+			if !errors.As(err, &e) {
+				t.Fatalf("%s, %s: should fail with a DecodeError but got %v", c.name, decoder, err)
+			}
+
+			if e.Path != c.path || e.Actual != c.actual {
+				t.Errorf("%s, %s: incorrect error, got %v", c.name, decoder, err)
+			}
+		}
+	}
+}
+
+func TestDecodeErrorUnknownType(t *testing.T) {
+	blob, _ := bson.Marshal(bson.D{{Key: "type", Value: "Circle"}, {Key: "coordinates", Value: bson.A{1, 2}}})
+
+	_, err := UnmarshalGeometry(blob)
+	if !errors.Is(err, ErrUnknownType) {
+		t.Errorf("should wrap ErrUnknownType but got %v", err)
+	}
+
+	if err.Error() != `type: unknown geometry type "Circle"` {
+		t.Errorf("incorrect message, got %q", err.Error())
+	}
+}
+
+func TestDecodeErrorFeatureCollection(t *testing.T) {
+	data := []byte(`{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 2]}, "properties": {}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[1, 2], [3, null]]}, "properties": {}}
+	]}`)
+
+	_, err := UnmarshalFeatureCollectionRawJSON(data)
+
+	var e *DecodeError
+	if !errors.As(err, &e) {
+		t.Fatalf("should fail with a DecodeError but got %v", err)
+	}
+
+	want := "features[1].geometry.coordinates[1][1]: not a valid coordinate, got null"
+	if err.Error() != want {
+		t.Errorf("incorrect message, got %q", err.Error())
+	}
+}
@@ -0,0 +1,381 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// A Feature corresponds to GeoJSON feature object
+type Feature struct {
+	ID         interface{}            `bson:"id,omitempty" json:"id,omitempty"`
+	Type       string                 `bson:"type" json:"type"`
+	BBox       []float64              `bson:"bbox,omitempty" json:"bbox,omitempty"`
+	Geometry   *Geometry              `bson:"geometry" json:"geometry"`
+	Properties map[string]interface{} `bson:"properties" json:"properties"`
+}
+
+// NewFeature creates and initializes a GeoJSON feature given the required attributes.
+func NewFeature(geometry *Geometry) *Feature {
+	return &Feature{
+		Type:       "Feature",
+		Geometry:   geometry,
+		Properties: make(map[string]interface{}),
+	}
+}
+
+// NewPointFeature creates and initializes a GeoJSON feature with a point geometry using the given coordinate.
+func NewPointFeature(coordinate Point) *Feature {
+	return NewFeature(NewPoint(coordinate))
+}
+
+// SetProperty provides the inverse of all the property functions
+// and is here for consistency.
+func (f *Feature) SetProperty(key string, value interface{}) {
+	if f.Properties == nil {
+		f.Properties = make(map[string]interface{})
+	}
+	f.Properties[key] = value
+}
+
+// defining a struct here lets us define the order of the BSON elements.
+type feature struct {
+	ID         interface{}            `bson:"id,omitempty" json:"id,omitempty"`
+	Type       string                 `bson:"type" json:"type"`
+	BBox       []float64              `bson:"bbox,omitempty" json:"bbox,omitempty"`
+	Geometry   *Geometry              `bson:"geometry" json:"geometry"`
+	Properties map[string]interface{} `bson:"properties" json:"properties"`
+}
+
+func (f *Feature) toPureFeature() *feature {
+	return &feature{
+		ID:         f.ID,
+		Type:       "Feature",
+		BBox:       f.BBox,
+		Geometry:   f.Geometry,
+		Properties: f.Properties,
+	}
+}
+
+// MarshalBSON converts the feature object into the proper BSON.
+// It will handle the encoding of all the child geometries.
+// MarshalBSON implements bson.Marshaler
+// nolint: gocritic
+func (f Feature) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(f.toPureFeature())
+}
+
+// MarshalJSON converts the feature object into RFC 7946 GeoJSON.
+// nolint: gocritic
+func (f Feature) MarshalJSON() ([]byte, error) {
+	return json.Marshal(f.toPureFeature())
+}
+
+// MarshalExtJSON converts the feature object into relaxed MongoDB Extended JSON.
+// nolint: gocritic
+func (f Feature) MarshalExtJSON() ([]byte, error) {
+	return bson.MarshalExtJSON(f, false, false)
+}
+
+// UnmarshalFeature decodes the binary BSON data into a GeoJSON feature.
+func UnmarshalFeature(data []byte) (*Feature, error) {
+	f := &Feature{}
+	err := bson.Unmarshal(data, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// UnmarshalFeatureWithOptions decodes the binary BSON data into a GeoJSON feature
+// using the given decode options for its geometry and its bbox.
+func UnmarshalFeatureWithOptions(data []byte, opts DecodeOptions) (*Feature, error) {
+	f := &Feature{}
+	err := f.unmarshalBSON(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// UnmarshalFeatureRawJSON decodes RFC 7946 GeoJSON data into a GeoJSON feature.
+func UnmarshalFeatureRawJSON(data []byte) (*Feature, error) {
+	f := &Feature{}
+	err := json.Unmarshal(data, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return f, nil
+}
+
+// UnmarshalBSON decodes the data into a GeoJSON feature.
+// This fulfills the bson.Unmarshaler interface.
+func (f *Feature) UnmarshalBSON(data []byte) error {
+	return f.unmarshalBSON(data, DecodeOptions{})
+}
+
+func (f *Feature) unmarshalBSON(data []byte, opts DecodeOptions) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var object map[string]interface{}
+	err := bson.Unmarshal(data, &object)
+	if err != nil {
+		return err
+	}
+
+	if err = decodeFeature(f, object, opts); err != nil {
+		return err
+	}
+
+	if doc, ok := bson.Raw(data).Lookup("geometry").DocumentOK(); ok && f.Geometry != nil {
+		keepRawDocuments(f.Geometry, doc)
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes the RFC 7946 GeoJSON data into a GeoJSON feature.
+// This fulfills the json.Unmarshaler interface.
+func (f *Feature) UnmarshalJSON(data []byte) error {
+	object, err := decodeJSONObject(data)
+	if err != nil || object == nil {
+		return err
+	}
+
+	return decodeFeature(f, object, DecodeOptions{})
+}
+
+// decodeFeature decodes the feature object, the options apply to its geometry
+// and its bbox.
+func decodeFeature(f *Feature, object map[string]interface{}, opts DecodeOptions) error {
+	if s, ok := object["type"].(string); !ok || s != "Feature" {
+		return decodeErrorAt(decodeError("Feature type", object["type"]), "type")
+	}
+	f.Type = "Feature"
+	f.ID = object["id"]
+
+	var err error
+	if f.BBox, err = decodeBBox(object["bbox"], opts); err != nil {
+		return decodeErrorAt(err, "bbox")
+	}
+
+	switch geo := object["geometry"].(type) {
+	case nil:
+		f.Geometry = nil
+	case map[string]interface{}:
+		f.Geometry = &Geometry{}
+		if err := decodeGeometry(f.Geometry, geo, opts, 0); err != nil {
+			return decodeErrorAt(err, "geometry")
+		}
+	default:
+		return decodeErrorAt(decodeError("geometry", geo), "geometry")
+	}
+
+	switch props := object["properties"].(type) {
+	case nil:
+		f.Properties = make(map[string]interface{})
+	case map[string]interface{}:
+		f.Properties = props
+	default:
+		return decodeErrorAt(decodeError("set of properties", props), "properties")
+	}
+
+	return nil
+}
+
+// decodeBBox decodes a bbox, the options converting coordinates apply to it.
+func decodeBBox(data interface{}, opts DecodeOptions) ([]float64, error) {
+	if data == nil {
+		return nil, nil
+	}
+
+	if _, ok := data.(primitive.A); !ok {
+		return nil, decodeError("bbox", data)
+	}
+
+	bbox, err := decodePosition(data, DecodeOptions{Decimal128: opts.Decimal128, NumericStrings: opts.NumericStrings})
+	if err != nil {
+		return nil, err
+	}
+	if len(bbox) != 4 && len(bbox) != 6 {
+		return nil, &DecodeError{Err: fmt.Errorf("bbox must have 4 or 6 elements, got %d", len(bbox))}
+	}
+
+	return bbox, nil
+}
+
+// A FeatureCollection correlates to a GeoJSON feature collection.
+type FeatureCollection struct {
+	Type     string     `bson:"type" json:"type"`
+	BBox     []float64  `bson:"bbox,omitempty" json:"bbox,omitempty"`
+	Features []*Feature `bson:"features" json:"features"`
+}
+
+// NewFeatureCollection creates and initializes a new feature collection.
+func NewFeatureCollection() *FeatureCollection {
+	return &FeatureCollection{
+		Type:     "FeatureCollection",
+		Features: make([]*Feature, 0),
+	}
+}
+
+// AddFeature appends a feature to the collection.
+func (fc *FeatureCollection) AddFeature(feature *Feature) *FeatureCollection {
+	fc.Features = append(fc.Features, feature)
+	return fc
+}
+
+// defining a struct here lets us define the order of the BSON elements.
+type featureCollection struct {
+	Type     string     `bson:"type" json:"type"`
+	BBox     []float64  `bson:"bbox,omitempty" json:"bbox,omitempty"`
+	Features []*Feature `bson:"features" json:"features"`
+}
+
+func (fc *FeatureCollection) toPureFeatureCollection() *featureCollection {
+	features := fc.Features
+	if features == nil {
+		features = make([]*Feature, 0)
+	}
+	return &featureCollection{
+		Type:     "FeatureCollection",
+		BBox:     fc.BBox,
+		Features: features,
+	}
+}
+
+// MarshalBSON converts the feature collection object into the proper BSON.
+// It will handle the encoding of all the child features and geometries.
+// MarshalBSON implements bson.Marshaler
+// nolint: gocritic
+func (fc FeatureCollection) MarshalBSON() ([]byte, error) {
+	return bson.Marshal(fc.toPureFeatureCollection())
+}
+
+// MarshalJSON converts the feature collection object into RFC 7946 GeoJSON.
+// nolint: gocritic
+func (fc FeatureCollection) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fc.toPureFeatureCollection())
+}
+
+// MarshalExtJSON converts the feature collection object into relaxed MongoDB Extended JSON.
+// nolint: gocritic
+func (fc FeatureCollection) MarshalExtJSON() ([]byte, error) {
+	return bson.MarshalExtJSON(fc, false, false)
+}
+
+// UnmarshalFeatureCollection decodes the binary BSON data into a GeoJSON feature collection.
+func UnmarshalFeatureCollection(data []byte) (*FeatureCollection, error) {
+	fc := &FeatureCollection{}
+	err := bson.Unmarshal(data, fc)
+	if err != nil {
+		return nil, err
+	}
+
+	return fc, nil
+}
+
+// UnmarshalFeatureCollectionWithOptions decodes the binary BSON data into a GeoJSON
+// feature collection using the given decode options for its bbox and its features.
+func UnmarshalFeatureCollectionWithOptions(data []byte, opts DecodeOptions) (*FeatureCollection, error) {
+	fc := &FeatureCollection{}
+	err := fc.unmarshalBSON(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return fc, nil
+}
+
+// UnmarshalFeatureCollectionRawJSON decodes RFC 7946 GeoJSON data into a GeoJSON feature collection.
+func UnmarshalFeatureCollectionRawJSON(data []byte) (*FeatureCollection, error) {
+	fc := &FeatureCollection{}
+	err := json.Unmarshal(data, fc)
+	if err != nil {
+		return nil, err
+	}
+
+	return fc, nil
+}
+
+// UnmarshalBSON decodes the data into a GeoJSON feature collection.
+// This fulfills the bson.Unmarshaler interface.
+func (fc *FeatureCollection) UnmarshalBSON(data []byte) error {
+	return fc.unmarshalBSON(data, DecodeOptions{})
+}
+
+func (fc *FeatureCollection) unmarshalBSON(data []byte, opts DecodeOptions) error {
+	if len(data) == 0 {
+		return nil
+	}
+	var object map[string]interface{}
+	err := bson.Unmarshal(data, &object)
+	if err != nil {
+		return err
+	}
+
+	if err = decodeFeatureCollection(fc, object, opts); err != nil {
+		return err
+	}
+
+	for i, f := range fc.Features {
+		if doc, ok := bson.Raw(data).Lookup("features", strconv.Itoa(i), "geometry").DocumentOK(); ok && f.Geometry != nil {
+			keepRawDocuments(f.Geometry, doc)
+		}
+	}
+
+	return nil
+}
+
+// UnmarshalJSON decodes the RFC 7946 GeoJSON data into a GeoJSON feature collection.
+// This fulfills the json.Unmarshaler interface.
+func (fc *FeatureCollection) UnmarshalJSON(data []byte) error {
+	object, err := decodeJSONObject(data)
+	if err != nil || object == nil {
+		return err
+	}
+
+	return decodeFeatureCollection(fc, object, DecodeOptions{})
+}
+
+// decodeFeatureCollection decodes the feature collection object, the options
+// apply to its bbox and to its features.
+func decodeFeatureCollection(fc *FeatureCollection, object map[string]interface{}, opts DecodeOptions) error {
+	if s, ok := object["type"].(string); !ok || s != "FeatureCollection" {
+		return decodeErrorAt(decodeError("FeatureCollection type", object["type"]), "type")
+	}
+	fc.Type = "FeatureCollection"
+
+	var err error
+	if fc.BBox, err = decodeBBox(object["bbox"], opts); err != nil {
+		return decodeErrorAt(err, "bbox")
+	}
+
+	vs, ok := object["features"].(primitive.A)
+	if !ok {
+		return decodeErrorAt(decodeError("set of features", object["features"]), "features")
+	}
+
+	fc.Features = make([]*Feature, 0, len(vs))
+	for i, v := range vs {
+		vmap, ok := v.(map[string]interface{})
+		if !ok {
+			return decodeErrorAt(decodeErrorAtIndex(decodeError("feature", v), i), "features")
+		}
+
+		f := &Feature{}
+		if err := decodeFeature(f, vmap, opts); err != nil {
+			return decodeErrorAt(decodeErrorAtIndex(err, i), "features")
+		}
+		fc.Features = append(fc.Features, f)
+	}
+
+	return nil
+}
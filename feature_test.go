@@ -0,0 +1,193 @@
+package geojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestFeatureMarshalBSONRoundTrip(t *testing.T) {
+	f := NewPointFeature(Point{1, 2})
+	f.ID = "feature-1"
+	f.BBox = []float64{1, 2, 1, 2}
+	f.SetProperty("name", "spot")
+
+	blob, err := bson.Marshal(f)
+	if err != nil {
+		t.Fatalf("should marshal to bson just fine but got %v", err)
+	}
+
+	got, err := UnmarshalFeature(blob)
+	if err != nil {
+		t.Fatalf("should unmarshal feature without issue, err %v", err)
+	}
+
+	if got.ID != "feature-1" {
+		t.Errorf("incorrect id, got %v", got.ID)
+	}
+
+	if got.Geometry == nil || !got.Geometry.IsPoint() || len(got.Geometry.Point) != 2 {
+		t.Errorf("incorrect geometry, got %+v", got.Geometry)
+	}
+
+	if got.Properties["name"] != "spot" {
+		t.Errorf("incorrect properties, got %v", got.Properties)
+	}
+
+	if len(got.BBox) != 4 {
+		t.Errorf("should have 4 bbox elements but got %d", len(got.BBox))
+	}
+}
+
+func TestFeatureMarshalJSON(t *testing.T) {
+	f := NewPointFeature(Point{1, 2})
+	f.ID = 7
+	blob, err := f.MarshalJSON()
+	if err != nil {
+		t.Fatalf("should marshal to json just fine but got %v", err)
+	}
+
+	if !bytes.Contains(blob, []byte(`"type":"Feature"`)) {
+		t.Errorf("json should have type Feature")
+	}
+
+	if !bytes.Contains(blob, []byte(`"geometry":{"type":"Point"`)) {
+		t.Errorf("json should have the geometry, blob=%s", blob)
+	}
+
+	if !bytes.Contains(blob, []byte(`"properties":{}`)) {
+		t.Errorf("json should have empty properties, blob=%s", blob)
+	}
+}
+
+func TestUnmarshalFeatureNullGeometry(t *testing.T) {
+	rawJSON := `{"type": "Feature", "geometry": null, "properties": null}`
+
+	f, err := UnmarshalFeatureRawJSON([]byte(rawJSON))
+	if err != nil {
+		t.Fatalf("should unmarshal feature without issue, err %v", err)
+	}
+
+	if f.Geometry != nil {
+		t.Errorf("geometry should be nil, got %+v", f.Geometry)
+	}
+
+	if f.Properties == nil {
+		t.Errorf("properties should be initialized")
+	}
+}
+
+func TestUnmarshalFeatureCollection(t *testing.T) {
+	rawJSON := `{"type": "FeatureCollection", "features": [
+		{"type": "Feature", "id": 1, "geometry": {"type": "Point", "coordinates": [102.0, 0.5]}, "properties": {"prop0": "value0"}},
+		{"type": "Feature", "geometry": {"type": "LineString", "coordinates": [[102.0, 0.0], [103.0, 1.0]]}, "properties": {"prop1": 0.0}}
+	]}`
+
+	fc, err := UnmarshalFeatureCollectionRawJSON([]byte(rawJSON))
+	if err != nil {
+		t.Fatalf("should unmarshal feature collection without issue, err %v", err)
+	}
+
+	if len(fc.Features) != 2 {
+		t.Fatalf("should have 2 features but got %d", len(fc.Features))
+	}
+
+	if fc.Features[0].Properties["prop0"] != "value0" {
+		t.Errorf("incorrect properties, got %v", fc.Features[0].Properties)
+	}
+
+	if !fc.Features[1].Geometry.IsLineString() {
+		t.Errorf("incorrect geometry type, got %v", fc.Features[1].Geometry.Type)
+	}
+
+	blob, err := bson.Marshal(fc)
+	if err != nil {
+		t.Fatalf("should marshal to bson just fine but got %v", err)
+	}
+
+	got, err := UnmarshalFeatureCollection(blob)
+	if err != nil {
+		t.Fatalf("should unmarshal feature collection without issue, err %v", err)
+	}
+
+	if len(got.Features) != 2 || got.Features[0].ID == nil {
+		t.Errorf("feature collection did not round trip, got %+v", got.Features)
+	}
+}
+
+func TestUnmarshalFeatureCollectionInvalidType(t *testing.T) {
+	rawJSON := `{"type": "Feature", "features": []}`
+
+	if _, err := UnmarshalFeatureCollectionRawJSON([]byte(rawJSON)); err == nil {
+		t.Errorf("should fail to unmarshal a collection with the wrong type")
+	}
+}
+
+func TestUnmarshalFeatureWithOptions(t *testing.T) {
+	geometry := bson.D{{Key: "type", Value: "point"}, {Key: "coordinates", Value: bson.A{1, 2}}}
+	feature := bson.D{{Key: "type", Value: "Feature"}, {Key: "geometry", Value: geometry}, {Key: "properties", Value: bson.D{}}}
+	opts := DecodeOptions{Lenient: true}
+
+	data, _ := bson.Marshal(feature)
+	if _, err := UnmarshalFeature(data); err == nil {
+		t.Errorf("should fail to unmarshal a lowercase type without options")
+	}
+
+	f, err := UnmarshalFeatureWithOptions(data, opts)
+	if err != nil {
+		t.Fatalf("should unmarshal feature without issue, err %v", err)
+	}
+
+	if !f.Geometry.IsPoint() {
+		t.Errorf("incorrect geometry type, got %v", f.Geometry.Type)
+	}
+
+	data, _ = bson.Marshal(bson.D{{Key: "type", Value: "FeatureCollection"}, {Key: "features", Value: bson.A{feature}}})
+	if _, err := UnmarshalFeatureCollection(data); err == nil {
+		t.Errorf("should fail to unmarshal a lowercase type without options")
+	}
+
+	fc, err := UnmarshalFeatureCollectionWithOptions(data, opts)
+	if err != nil {
+		t.Fatalf("should unmarshal feature collection without issue, err %v", err)
+	}
+
+	if len(fc.Features) != 1 || !fc.Features[0].Geometry.IsPoint() {
+		t.Errorf("incorrect features, got %+v", fc.Features)
+	}
+}
+
+func TestFeatureIntegerIDRoundTrip(t *testing.T) {
+	rawJSON := `{"type":"Feature","id":12345678901234567,"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"count":9007199254740993,"ratio":0.5}}`
+
+	f, err := UnmarshalFeatureRawJSON([]byte(rawJSON))
+	if err != nil {
+		t.Fatalf("should unmarshal feature without issue, err %v", err)
+	}
+
+	blob, err := bson.Marshal(f)
+	if err != nil {
+		t.Fatalf("should marshal to bson just fine but got %v", err)
+	}
+
+	if id, ok := bson.Raw(blob).Lookup("id").Int64OK(); !ok || id != 12345678901234567 {
+		t.Errorf("integer id should be stored as an int64, got %v", bson.Raw(blob).Lookup("id"))
+	}
+
+	got, err := UnmarshalFeature(blob)
+	if err != nil {
+		t.Fatalf("should unmarshal feature without issue, err %v", err)
+	}
+
+	data, err := json.Marshal(got)
+	if err != nil {
+		t.Fatalf("should marshal to json just fine but got %v", err)
+	}
+
+	want := `{"id":12345678901234567,"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"count":9007199254740993,"ratio":0.5}}`
+	if string(data) != want {
+		t.Errorf("feature should round trip without loss\n got %s\nwant %s", data, want)
+	}
+}
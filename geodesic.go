@@ -0,0 +1,1092 @@
+package geojson
+
+// The geodesic calculations on the ellipsoid in this file are ported from
+// geodesic.c of GeographicLib (https://geographiclib.sourceforge.io) by Charles Karney,
+// licensed under the MIT/X11 License, see
+// C. F. F. Karney, Algorithms for geodesics, J. Geodesy 87, 43-55 (2013).
+// Only the inverse problem and the polygon area are kept, for an oblate ellipsoid.
+
+import (
+	"math"
+)
+
+const (
+	geodOrder = 6
+	nA1       = geodOrder
+	nC1       = geodOrder
+	nA2       = geodOrder
+	nC2       = geodOrder
+	nA3       = geodOrder
+	nA3x      = nA3
+	nC3       = geodOrder
+	nC3x      = (nC3 * (nC3 - 1)) / 2
+	nC4       = geodOrder
+	nC4x      = (nC4 * (nC4 + 1)) / 2
+	nC        = geodOrder + 1
+
+	geodMaxit1 = 20
+	geodMaxit2 = geodMaxit1 + 53 + 10
+	degree     = math.Pi / 180
+)
+
+var (
+	geodTiny    = math.Sqrt(math.SmallestNonzeroFloat64 * (1 << 52)) // sqrt of the smallest normal
+	geodTol0    = math.Nextafter(1, 2) - 1
+	geodTol1    = 200 * geodTol0
+	geodTol2    = math.Sqrt(geodTol0)
+	geodTolb    = geodTol0 * geodTol2
+	geodXthresh = 1000 * geodTol2
+)
+
+// The WGS84 ellipsoid used by GPS and by MongoDB 2dsphere data.
+const (
+	wgs84A = 6378137
+	wgs84F = 1 / 298.257223563
+)
+
+// wgs84 is the geodesic on the WGS84 ellipsoid.
+var wgs84 = newGeodesic(wgs84A, wgs84F)
+
+type geodesic struct {
+	a, f, f1, e2, ep2, n, b, c2, etol2 float64
+
+	A3x [nA3x]float64
+	C3x [nC3x]float64
+	C4x [nC4x]float64
+}
+
+func newGeodesic(a, f float64) *geodesic {
+	g := &geodesic{a: a, f: f}
+	g.f1 = 1 - f
+	g.e2 = f * (2 - f)
+	g.ep2 = g.e2 / (g.f1 * g.f1)
+	g.n = f / (2 - f)
+	g.b = a * g.f1
+
+	var c float64
+	switch {
+	case g.e2 == 0:
+		c = 1
+	case g.e2 > 0:
+		c = math.Atanh(math.Sqrt(g.e2)) / math.Sqrt(g.e2)
+	default:
+		c = math.Atan(math.Sqrt(-g.e2)) / math.Sqrt(-g.e2)
+	}
+	g.c2 = (a*a + g.b*g.b*c) / 2
+	g.etol2 = 0.1 * geodTol2 / math.Sqrt(math.Max(0.001, math.Abs(f))*math.Min(1, 1-f/2)/2)
+
+	g.a3coeff()
+	g.c3coeff()
+	g.c4coeff()
+
+	return g
+}
+
+// inverse solves the inverse geodesic problem between (lat1, lon1) and (lat2, lon2) in degrees.
+// It returns the distance s12 in meters, the azimuths azi1 and azi2 in degrees and,
+// when withArea is set, the area S12 between the geodesic and the equator.
+func (g *geodesic) inverse(lat1, lon1, lat2, lon2 float64, withArea bool) (s12, azi1, azi2, S12 float64) {
+	var (
+		Ca                         [nC]float64
+		s12x, m12x                 float64
+		sig12, calp1, salp1        float64
+		calp2, salp2               float64
+		omg12, somg12, comg12      = 0.0, 2.0, 0.0
+		slam12, clam12, lon12s     float64
+		meridian                   bool
+		sbet1, cbet1, sbet2, cbet2 float64
+	)
+
+	lon12 := angDiff(lon1, lon2, &lon12s)
+	// Make longitude difference positive.
+	lonsign := 1.0
+	if lon12 < 0 {
+		lonsign = -1
+	}
+	// If very close to being on the same half-meridian, then make it so.
+	lon12 = lonsign * angRound(lon12)
+	lon12s = angRound((180 - lon12) - lonsign*lon12s)
+	lam12 := lon12 * degree
+	if lon12 > 90 {
+		slam12, clam12 = sincosd(lon12s)
+		clam12 = -clam12
+	} else {
+		slam12, clam12 = sincosd(lon12)
+	}
+
+	// If really close to the equator, treat as on equator.
+	lat1 = angRound(latFix(lat1))
+	lat2 = angRound(latFix(lat2))
+	// Swap points so that point with higher (abs) latitude is point 1.
+	// If one latitude is a nan, then it becomes lat1.
+	swapp := 1.0
+	if math.Abs(lat1) < math.Abs(lat2) || math.IsNaN(lat2) {
+		swapp = -1
+		lonsign *= -1
+		lat1, lat2 = lat2, lat1
+	}
+	// Make lat1 <= 0
+	latsign := -1.0
+	if lat1 < 0 {
+		latsign = 1
+	}
+	lat1 *= latsign
+	lat2 *= latsign
+	// Now we have
+	//
+	//     0 <= lon12 <= 180
+	//     -90 <= lat1 <= 0
+	//     lat1 <= lat2 <= -lat1
+	//
+	// lonsign, swapp, latsign register the transformation to bring the
+	// coordinates to this canonical form. In all cases, 1 means no change was made.
+
+	sbet1, cbet1 = sincosd(lat1)
+	sbet1 *= g.f1
+	// Ensure cbet1 = +epsilon at poles
+	sbet1, cbet1 = norm2(sbet1, cbet1)
+	cbet1 = math.Max(geodTiny, cbet1)
+
+	sbet2, cbet2 = sincosd(lat2)
+	sbet2 *= g.f1
+	// Ensure cbet2 = +epsilon at poles
+	sbet2, cbet2 = norm2(sbet2, cbet2)
+	cbet2 = math.Max(geodTiny, cbet2)
+
+	// If cbet1 < -sbet1, then cbet2 - cbet1 is a sensitive measure of the
+	// |bet1| - |bet2|. Alternatively (cbet1 >= -sbet1), abs(sbet2) + sbet1 is
+	// a better measure. Sometimes these quantities vanish and in that case we
+	// force bet2 = +/- bet1 exactly.
+	if cbet1 < -sbet1 {
+		if cbet2 == cbet1 {
+			sbet2 = math.Copysign(sbet1, sbet2)
+		}
+	} else if math.Abs(sbet2) == -sbet1 {
+		cbet2 = cbet1
+	}
+
+	dn1 := math.Sqrt(1 + g.ep2*sbet1*sbet1)
+	dn2 := math.Sqrt(1 + g.ep2*sbet2*sbet2)
+
+	meridian = lat1 == -90 || slam12 == 0
+
+	if meridian {
+		// Endpoints are on a single full meridian, so the geodesic might lie on a meridian.
+		calp1, salp1 = clam12, slam12 // Head to the target longitude
+		calp2, salp2 = 1, 0           // At the target we're heading north
+
+		// tan(bet) = tan(sig) * cos(alp)
+		ssig1, csig1 := sbet1, calp1*cbet1
+		ssig2, csig2 := sbet2, calp2*cbet2
+
+		// sig12 = sig2 - sig1
+		sig12 = math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+		s12x, m12x, _ = g.lengths(g.n, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2, &Ca)
+		// Add the check for sig12 since zero length geodesics might yield m12 < 0.
+		// In fact, we will have sig12 > pi/2 for meridional geodesic which is not a shortest path.
+		if sig12 < 1 || m12x >= 0 {
+			// Need at least 2, to handle 90 0 90 180
+			if sig12 < 3*geodTiny || (sig12 < geodTol0 && (s12x < 0 || m12x < 0)) {
+				sig12, m12x, s12x = 0, 0, 0
+			}
+			m12x *= g.b
+			s12x *= g.b
+		} else {
+			// m12 < 0, i.e., prolate and too close to anti-podal
+			meridian = false
+		}
+	}
+
+	if !meridian && sbet1 == 0 && (g.f <= 0 || lon12s >= g.f*180) {
+		// Geodesic runs along equator
+		calp1, calp2, salp1, salp2 = 0, 0, 1, 1
+		s12x = g.a * lam12
+		sig12 = lam12 / g.f1
+		omg12 = sig12
+		m12x = g.b * math.Sin(sig12)
+	} else if !meridian {
+		// Now point1 and point2 belong within a hemisphere bounded by a
+		// meridian and geodesic is neither meridional or equatorial.
+
+		// Figure a starting point for Newton's method
+		var dnm float64
+		sig12 = g.inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12,
+			&salp1, &calp1, &salp2, &calp2, &dnm, &Ca)
+
+		if sig12 >= 0 {
+			// Short lines (inverseStart sets salp2, calp2, dnm)
+			s12x = sig12 * g.b * dnm
+			m12x = dnm * dnm * g.b * math.Sin(sig12/dnm)
+			omg12 = lam12 / (g.f1 * dnm)
+		} else {
+			// Newton's method. This is a straightforward solution of f(alp1) =
+			// lambda12(alp1) - lam12 = 0 with one wrinkle. f(alp) has exactly one
+			// root in the interval (0, pi) and its derivative is positive at the
+			// root. Thus f(alp) is positive for alp > alp1 and negative for alp <
+			// alp1. During the course of the iteration, a range (alp1a, alp1b) is
+			// maintained which brackets the root and with each evaluation of
+			// f(alp) the range is shrunk, if possible. Newton's method is
+			// restarted whenever the derivative of f is negative (because the new
+			// value of alp1 is then further from the solution) or if the new
+			// estimate of alp1 lies outside (0,pi); in this case, the new starting
+			// guess is taken to be (alp1a + alp1b) / 2.
+			var ssig1, csig1, ssig2, csig2, eps, domg12 float64
+			// Bracketing range
+			salp1a, calp1a, salp1b, calp1b := geodTiny, 1.0, geodTiny, -1.0
+			tripn, tripb := false, false
+			for numit := 0; ; numit++ {
+				var dv float64
+				v := g.lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam12, clam12,
+					&salp2, &calp2, &sig12, &ssig1, &csig1, &ssig2, &csig2, &eps, &domg12,
+					numit < geodMaxit1, &dv, &Ca)
+				tol := geodTol0
+				if tripn {
+					tol *= 8
+				}
+				// Reversed test to allow escape with NaNs
+				if tripb || !(math.Abs(v) >= tol) || numit == geodMaxit2 {
+					break
+				}
+				// Update bracketing values
+				if v > 0 && (numit > geodMaxit1 || calp1/salp1 > calp1b/salp1b) {
+					salp1b, calp1b = salp1, calp1
+				} else if v < 0 && (numit > geodMaxit1 || calp1/salp1 < calp1a/salp1a) {
+					salp1a, calp1a = salp1, calp1
+				}
+				if numit < geodMaxit1 && dv > 0 {
+					dalp1 := -v / dv
+					if math.Abs(dalp1) < math.Pi {
+						sdalp1, cdalp1 := math.Sincos(dalp1)
+						nsalp1 := salp1*cdalp1 + calp1*sdalp1
+						if nsalp1 > 0 {
+							calp1 = calp1*cdalp1 - salp1*sdalp1
+							salp1 = nsalp1
+							salp1, calp1 = norm2(salp1, calp1)
+							// In some regimes we don't get quadratic convergence because
+							// slope -> 0. So use convergence conditions based on epsilon
+							// instead of sqrt(epsilon).
+							tripn = math.Abs(v) <= 16*geodTol0
+							continue
+						}
+					}
+				}
+				// Either dv was not positive or updated value was outside legal
+				// range. Use the midpoint of the bracket as the next estimate.
+				salp1 = (salp1a + salp1b) / 2
+				calp1 = (calp1a + calp1b) / 2
+				salp1, calp1 = norm2(salp1, calp1)
+				tripn = false
+				tripb = math.Abs(salp1a-salp1)+(calp1a-calp1) < geodTolb ||
+					math.Abs(salp1-salp1b)+(calp1-calp1b) < geodTolb
+			}
+			s12x, m12x, _ = g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2, &Ca)
+			m12x *= g.b
+			s12x *= g.b
+			if withArea {
+				// omg12 = lam12 - domg12
+				sdomg12, cdomg12 := math.Sincos(domg12)
+				somg12 = slam12*cdomg12 - clam12*sdomg12
+				comg12 = clam12*cdomg12 + slam12*sdomg12
+			}
+		}
+	}
+
+	s12 = 0 + s12x // Convert -0 to 0
+
+	if withArea {
+		// From lambda12: sin(alp1) * cos(bet1) = sin(alp0)
+		salp0 := salp1 * cbet1
+		calp0 := math.Hypot(calp1, salp1*sbet1) // calp0 > 0
+		if calp0 != 0 && salp0 != 0 {
+			// From lambda12: tan(bet) = tan(sig) * cos(alp)
+			ssig1, csig1 := norm2(sbet1, calp1*cbet1)
+			ssig2, csig2 := norm2(sbet2, calp2*cbet2)
+			k2 := calp0 * calp0 * g.ep2
+			eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+			// Multiplier = a^2 * e^2 * cos(alpha0) * sin(alpha0).
+			A4 := g.a * g.a * calp0 * salp0 * g.e2
+			g.c4f(eps, &Ca)
+			B41 := sinCosSeries(false, ssig1, csig1, Ca[:], nC4)
+			B42 := sinCosSeries(false, ssig2, csig2, Ca[:], nC4)
+			S12 = A4 * (B42 - B41)
+		}
+		// Otherwise avoid problems with indeterminate sig1, sig2 on equator
+
+		if !meridian && somg12 == 2 {
+			somg12, comg12 = math.Sincos(omg12)
+		}
+
+		var alp12 float64
+		if !meridian && comg12 > -0.7071 && sbet2-sbet1 < 1.75 {
+			// Long difference and lat difference not too big.
+			// Use tan(Gamma/2) = tan(omg12/2)
+			// * (tan(bet1/2)+tan(bet2/2))/(1+tan(bet1/2)*tan(bet2/2))
+			// with tan(x/2) = sin(x)/(1+cos(x))
+			domg12, dbet1, dbet2 := 1+comg12, 1+cbet1, 1+cbet2
+			alp12 = 2 * math.Atan2(somg12*(sbet1*dbet2+sbet2*dbet1), domg12*(sbet1*sbet2+dbet1*dbet2))
+		} else {
+			// alp12 = alp2 - alp1, used in atan2 so no need to normalize
+			salp12 := salp2*calp1 - calp2*salp1
+			calp12 := calp2*calp1 + salp2*salp1
+			// The right thing appears to happen if alp1 = +/-180 and alp2 = 0, viz
+			// salp12 = -0 and alp12 = -180. However this depends on the sign
+			// being attached to 0 correctly. The following ensures the correct behavior.
+			if salp12 == 0 && calp12 < 0 {
+				salp12 = geodTiny * calp1
+				calp12 = -1
+			}
+			alp12 = math.Atan2(salp12, calp12)
+		}
+		S12 += g.c2 * alp12
+		S12 *= swapp * lonsign * latsign
+		S12 += 0 // Convert -0 to 0
+	}
+
+	// Convert calp, salp to azimuth accounting for lonsign, swapp, latsign.
+	if swapp < 0 {
+		salp1, salp2 = salp2, salp1
+		calp1, calp2 = calp2, calp1
+	}
+	salp1 *= swapp * lonsign
+	calp1 *= swapp * latsign
+	salp2 *= swapp * lonsign
+	calp2 *= swapp * latsign
+
+	azi1 = atan2d(salp1, calp1)
+	azi2 = atan2d(salp2, calp2)
+
+	return s12, azi1, azi2, S12
+}
+
+// lengths returns s12b = distance/b, m12b = reduced length/b and m0, the
+// coefficient of the secular term in the expression for the reduced length.
+func (g *geodesic) lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2 float64,
+	Ca *[nC]float64) (s12b, m12b, m0 float64) {
+	var Cb [nC]float64
+
+	A1 := a1m1f(eps)
+	c1f(eps, Ca)
+	A2 := a2m1f(eps)
+	c2f(eps, &Cb)
+	m0 = A1 - A2
+	A2 = 1 + A2
+	A1 = 1 + A1
+
+	B1 := sinCosSeries(true, ssig2, csig2, Ca[:], nC1) - sinCosSeries(true, ssig1, csig1, Ca[:], nC1)
+	// Missing a factor of b
+	s12b = A1 * (sig12 + B1)
+	B2 := sinCosSeries(true, ssig2, csig2, Cb[:], nC2) - sinCosSeries(true, ssig1, csig1, Cb[:], nC2)
+	J12 := m0*sig12 + (A1*B1 - A2*B2)
+	// Missing a factor of b.
+	// Add parens around (csig1 * ssig2) and (ssig1 * csig2) to ensure
+	// accurate cancellation in the case of coincident points.
+	m12b = dn2*(csig1*ssig2) - dn1*(ssig1*csig2) - csig1*csig2*J12
+
+	return s12b, m12b, m0
+}
+
+func (g *geodesic) inverseStart(sbet1, cbet1, dn1, sbet2, cbet2, dn2, lam12, slam12, clam12 float64,
+	psalp1, pcalp1, psalp2, pcalp2, pdnm *float64, Ca *[nC]float64) float64 {
+	var salp1, calp1, salp2, calp2, dnm float64
+
+	// Return a starting point for Newton's method in salp1 and calp1 (function
+	// value is -1). If Newton's method doesn't need to be used, return also
+	// salp2 and calp2 and function value is sig12.
+	sig12 := -1.0
+	// bet12 = bet2 - bet1 in [0, pi); bet12a = bet2 + bet1 in (-pi, 0]
+	sbet12 := sbet2*cbet1 - cbet2*sbet1
+	cbet12 := cbet2*cbet1 + sbet2*sbet1
+	sbet12a := sbet2*cbet1 + cbet2*sbet1
+	shortline := cbet12 >= 0 && sbet12 < 0.5 && cbet2*lam12 < 0.5
+
+	var somg12, comg12 float64
+	if shortline {
+		sbetm2 := (sbet1 + sbet2) * (sbet1 + sbet2)
+		// sin((bet1+bet2)/2)^2
+		// =  (sbet1 + sbet2)^2 / ((sbet1 + sbet2)^2 + (cbet1 + cbet2)^2)
+		sbetm2 /= sbetm2 + (cbet1+cbet2)*(cbet1+cbet2)
+		dnm = math.Sqrt(1 + g.ep2*sbetm2)
+		omg12 := lam12 / (g.f1 * dnm)
+		somg12, comg12 = math.Sincos(omg12)
+	} else {
+		somg12, comg12 = slam12, clam12
+	}
+
+	salp1 = cbet2 * somg12
+	if comg12 >= 0 {
+		calp1 = sbet12 + cbet2*sbet1*somg12*somg12/(1+comg12)
+	} else {
+		calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+	}
+
+	ssig12 := math.Hypot(salp1, calp1)
+	csig12 := sbet1*sbet2 + cbet1*cbet2*comg12
+
+	if shortline && ssig12 < g.etol2 {
+		// really short lines
+		salp2 = cbet1 * somg12
+		if comg12 >= 0 {
+			calp2 = sbet12 - cbet1*sbet2*(somg12*somg12/(1+comg12))
+		} else {
+			calp2 = sbet12 - cbet1*sbet2*(1-comg12)
+		}
+		salp2, calp2 = norm2(salp2, calp2)
+		// Set return value
+		sig12 = math.Atan2(ssig12, csig12)
+	} else if math.Abs(g.n) > 0.1 || // No astroid calc if too eccentric
+		csig12 >= 0 ||
+		ssig12 >= 6*math.Abs(g.n)*math.Pi*cbet1*cbet1 {
+		// Nothing to do, zeroth order spherical approximation is OK
+	} else {
+		// Scale lam12 and bet2 to x, y coordinate system where antipodal point
+		// is at origin and singular point is at y = 0, x = -1.
+		lam12x := math.Atan2(-slam12, -clam12) // lam12 - pi
+		// x = dlong, y = dlat
+		k2 := sbet1 * sbet1 * g.ep2
+		eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+		lamscale := g.f * cbet1 * g.a3f(eps) * math.Pi
+		betscale := lamscale * cbet1
+
+		x := lam12x / lamscale
+		y := sbet12a / betscale
+
+		if y > -geodTol1 && x > -1-geodXthresh {
+			// strip near cut
+			salp1 = math.Min(1, -x)
+			calp1 = -math.Sqrt(1 - salp1*salp1)
+		} else {
+			// Estimate alp1, by solving the astroid problem.
+			k := astroid(x, y)
+			omg12a := lamscale * (-x * k / (1 + k))
+			somg12, comg12 = math.Sincos(omg12a)
+			comg12 = -comg12
+			// Update spherical estimate of alp1 using omg12 instead of lam12
+			salp1 = cbet2 * somg12
+			calp1 = sbet12a - cbet2*sbet1*somg12*somg12/(1-comg12)
+		}
+	}
+	// Sanity check on starting guess. Backwards check allows NaN through.
+	if !(salp1 <= 0) {
+		salp1, calp1 = norm2(salp1, calp1)
+	} else {
+		salp1, calp1 = 1, 0
+	}
+
+	*psalp1, *pcalp1 = salp1, calp1
+	if shortline {
+		*pdnm = dnm
+	}
+	if sig12 >= 0 {
+		*psalp2, *pcalp2 = salp2, calp2
+	}
+
+	return sig12
+}
+
+func (g *geodesic) lambda12(sbet1, cbet1, dn1, sbet2, cbet2, dn2, salp1, calp1, slam120, clam120 float64,
+	psalp2, pcalp2, psig12, pssig1, pcsig1, pssig2, pcsig2, peps, pdomg12 *float64,
+	diffp bool, pdlam12 *float64, Ca *[nC]float64) float64 {
+	if sbet1 == 0 && calp1 == 0 {
+		// Break degeneracy of equatorial line. This case has already been handled.
+		calp1 = -geodTiny
+	}
+
+	// sin(alp1) * cos(bet1) = sin(alp0)
+	salp0 := salp1 * cbet1
+	calp0 := math.Hypot(calp1, salp1*sbet1) // calp0 > 0
+
+	// tan(bet1) = tan(sig1) * cos(alp1)
+	// tan(omg1) = sin(alp0) * tan(sig1) = tan(omg1)=tan(alp1)*sin(bet1)
+	ssig1, somg1 := sbet1, salp0*sbet1
+	csig1 := calp1 * cbet1
+	comg1 := csig1
+	ssig1, csig1 = norm2(ssig1, csig1)
+	// norm2(somg1, comg1) -- don't need to normalize!
+
+	// Enforce symmetries in the case abs(bet2) = -bet1. Need to be careful
+	// about this case, since this can yield singularities in the Newton iteration.
+	// sin(alp2) * cos(bet2) = sin(alp0)
+	salp2 := salp1
+	if cbet2 != cbet1 {
+		salp2 = salp0 / cbet2
+	}
+	// calp2 = sqrt(1 - sq(salp2))
+	//       = sqrt(sq(calp0) - sq(sbet2)) / cbet2
+	// and subst for calp0 and rearrange to give (choose positive sqrt
+	// to give alp2 in [0, pi/2]).
+	calp2 := math.Abs(calp1)
+	if cbet2 != cbet1 || math.Abs(sbet2) != -sbet1 {
+		var d float64
+		if cbet1 < -sbet1 {
+			d = (cbet2 - cbet1) * (cbet1 + cbet2)
+		} else {
+			d = (sbet1 - sbet2) * (sbet1 + sbet2)
+		}
+		calp2 = math.Sqrt((calp1*cbet1)*(calp1*cbet1)+d) / cbet2
+	}
+	// tan(bet2) = tan(sig2) * cos(alp2)
+	// tan(omg2) = sin(alp0) * tan(sig2).
+	ssig2, somg2 := sbet2, salp0*sbet2
+	csig2 := calp2 * cbet2
+	comg2 := csig2
+	ssig2, csig2 = norm2(ssig2, csig2)
+	// norm2(somg2, comg2) -- don't need to normalize!
+
+	// sig12 = sig2 - sig1, limit to [0, pi]
+	sig12 := math.Atan2(math.Max(0, csig1*ssig2-ssig1*csig2), csig1*csig2+ssig1*ssig2)
+
+	// omg12 = omg2 - omg1, limit to [0, pi]
+	somg12 := math.Max(0, comg1*somg2-somg1*comg2)
+	comg12 := comg1*comg2 + somg1*somg2
+	// eta = omg12 - lam120
+	eta := math.Atan2(somg12*clam120-comg12*slam120, comg12*clam120+somg12*slam120)
+	k2 := calp0 * calp0 * g.ep2
+	eps := k2 / (2*(1+math.Sqrt(1+k2)) + k2)
+	g.c3f(eps, Ca)
+	B312 := sinCosSeries(true, ssig2, csig2, Ca[:], nC3-1) - sinCosSeries(true, ssig1, csig1, Ca[:], nC3-1)
+	domg12 := -g.f * g.a3f(eps) * salp0 * (sig12 + B312)
+	lam12 := eta + domg12
+
+	if diffp {
+		if calp2 == 0 {
+			*pdlam12 = -2 * g.f1 * dn1 / sbet1
+		} else {
+			_, m12b, _ := g.lengths(eps, sig12, ssig1, csig1, dn1, ssig2, csig2, dn2, cbet1, cbet2, Ca)
+			*pdlam12 = m12b * g.f1 / (calp2 * cbet2)
+		}
+	}
+
+	*psalp2, *pcalp2 = salp2, calp2
+	*psig12 = sig12
+	*pssig1, *pcsig1 = ssig1, csig1
+	*pssig2, *pcsig2 = ssig2, csig2
+	*peps = eps
+	*pdomg12 = domg12
+
+	return lam12
+}
+
+func (g *geodesic) a3f(eps float64) float64 {
+	// Evaluate A3
+	return polyval(nA3-1, g.A3x[:], eps)
+}
+
+func (g *geodesic) c3f(eps float64, c *[nC]float64) {
+	// Evaluate C3 coeffs
+	// Elements c[1] through c[nC3 - 1] are set
+	mult := 1.0
+	o := 0
+	for l := 1; l < nC3; l++ { // l is index of C3[l]
+		m := nC3 - l - 1 // order of polynomial in eps
+		mult *= eps
+		c[l] = mult * polyval(m, g.C3x[o:], eps)
+		o += m + 1
+	}
+}
+
+func (g *geodesic) c4f(eps float64, c *[nC]float64) {
+	// Evaluate C4 coeffs
+	// Elements c[0] through c[nC4 - 1] are set
+	mult := 1.0
+	o := 0
+	for l := 0; l < nC4; l++ { // l is index of C4[l]
+		m := nC4 - l - 1 // order of polynomial in eps
+		c[l] = mult * polyval(m, g.C4x[o:], eps)
+		o += m + 1
+		mult *= eps
+	}
+}
+
+// The scale factor A1-1 = mean value of (d/dsigma)I1 - 1
+func a1m1f(eps float64) float64 {
+	coeff := [...]float64{
+		// (1-eps)*A1-1, polynomial in eps2 of order 3
+		1, 4, 64, 0, 256,
+	}
+	m := nA1 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t + eps) / (1 - eps)
+}
+
+// The coefficients C1[l] in the Fourier expansion of B1
+func c1f(eps float64, c *[nC]float64) {
+	coeff := [...]float64{
+		// C1[1]/eps^1, polynomial in eps2 of order 2
+		-1, 6, -16, 32,
+		// C1[2]/eps^2, polynomial in eps2 of order 2
+		-9, 64, -128, 2048,
+		// C1[3]/eps^3, polynomial in eps2 of order 1
+		9, -16, 768,
+		// C1[4]/eps^4, polynomial in eps2 of order 1
+		3, -5, 512,
+		// C1[5]/eps^5, polynomial in eps2 of order 0
+		-7, 1280,
+		// C1[6]/eps^6, polynomial in eps2 of order 0
+		-7, 2048,
+	}
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= nC1; l++ { // l is index of C1p[l]
+		m := (nC1 - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// The scale factor A2-1 = mean value of (d/dsigma)I2 - 1
+func a2m1f(eps float64) float64 {
+	coeff := [...]float64{
+		// (eps+1)*A2-1, polynomial in eps2 of order 3
+		-11, -28, -192, 0, 256,
+	}
+	m := nA2 / 2
+	t := polyval(m, coeff[:], eps*eps) / coeff[m+1]
+	return (t - eps) / (1 + eps)
+}
+
+// The coefficients C2[l] in the Fourier expansion of B2
+func c2f(eps float64, c *[nC]float64) {
+	coeff := [...]float64{
+		// C2[1]/eps^1, polynomial in eps2 of order 2
+		1, 2, 16, 32,
+		// C2[2]/eps^2, polynomial in eps2 of order 2
+		35, 64, 384, 2048,
+		// C2[3]/eps^3, polynomial in eps2 of order 1
+		15, 80, 768,
+		// C2[4]/eps^4, polynomial in eps2 of order 1
+		7, 35, 512,
+		// C2[5]/eps^5, polynomial in eps2 of order 0
+		63, 1280,
+		// C2[6]/eps^6, polynomial in eps2 of order 0
+		77, 2048,
+	}
+	eps2 := eps * eps
+	d := eps
+	o := 0
+	for l := 1; l <= nC2; l++ { // l is index of C2[l]
+		m := (nC2 - l) / 2 // order of polynomial in eps^2
+		c[l] = d * polyval(m, coeff[o:], eps2) / coeff[o+m+1]
+		o += m + 2
+		d *= eps
+	}
+}
+
+// The scale factor A3 = mean value of (d/dsigma)I3
+func (g *geodesic) a3coeff() {
+	coeff := [...]float64{
+		// A3, coeff of eps^5, polynomial in n of order 0
+		-3, 128,
+		// A3, coeff of eps^4, polynomial in n of order 1
+		-2, -3, 64,
+		// A3, coeff of eps^3, polynomial in n of order 2
+		-1, -3, -1, 16,
+		// A3, coeff of eps^2, polynomial in n of order 2
+		3, -1, -2, 8,
+		// A3, coeff of eps^1, polynomial in n of order 1
+		1, -1, 2,
+		// A3, coeff of eps^0, polynomial in n of order 0
+		1, 1,
+	}
+	o, k := 0, 0
+	for j := nA3 - 1; j >= 0; j-- { // coeff of eps^j
+		m := nA3 - j - 1 // order of polynomial in n
+		if j < m {
+			m = j
+		}
+		g.A3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+		k++
+		o += m + 2
+	}
+}
+
+// The coefficients C3[l] in the Fourier expansion of B3
+func (g *geodesic) c3coeff() {
+	coeff := [...]float64{
+		// C3[1], coeff of eps^5, polynomial in n of order 0
+		3, 128,
+		// C3[1], coeff of eps^4, polynomial in n of order 1
+		2, 5, 128,
+		// C3[1], coeff of eps^3, polynomial in n of order 2
+		-1, 3, 3, 64,
+		// C3[1], coeff of eps^2, polynomial in n of order 2
+		-1, 0, 1, 8,
+		// C3[1], coeff of eps^1, polynomial in n of order 1
+		-1, 1, 4,
+		// C3[2], coeff of eps^5, polynomial in n of order 0
+		5, 256,
+		// C3[2], coeff of eps^4, polynomial in n of order 1
+		1, 3, 128,
+		// C3[2], coeff of eps^3, polynomial in n of order 2
+		-3, -2, 3, 64,
+		// C3[2], coeff of eps^2, polynomial in n of order 2
+		1, -3, 2, 32,
+		// C3[3], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[3], coeff of eps^4, polynomial in n of order 1
+		-10, 9, 384,
+		// C3[3], coeff of eps^3, polynomial in n of order 2
+		5, -9, 5, 192,
+		// C3[4], coeff of eps^5, polynomial in n of order 0
+		7, 512,
+		// C3[4], coeff of eps^4, polynomial in n of order 1
+		-14, 7, 512,
+		// C3[5], coeff of eps^5, polynomial in n of order 0
+		21, 2560,
+	}
+	o, k := 0, 0
+	for l := 1; l < nC3; l++ { // l is index of C3[l]
+		for j := nC3 - 1; j >= l; j-- { // coeff of eps^j
+			m := nC3 - j - 1 // order of polynomial in n
+			if j < m {
+				m = j
+			}
+			g.C3x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// The coefficients C4[l] in the Fourier expansion of I4
+func (g *geodesic) c4coeff() {
+	coeff := [...]float64{
+		// C4[0], coeff of eps^5, polynomial in n of order 0
+		97, 15015,
+		// C4[0], coeff of eps^4, polynomial in n of order 1
+		1088, 156, 45045,
+		// C4[0], coeff of eps^3, polynomial in n of order 2
+		-224, -4784, 1573, 45045,
+		// C4[0], coeff of eps^2, polynomial in n of order 3
+		-10656, 14144, -4576, -858, 45045,
+		// C4[0], coeff of eps^1, polynomial in n of order 4
+		64, 624, -4576, 6864, -3003, 15015,
+		// C4[0], coeff of eps^0, polynomial in n of order 5
+		100, 208, 572, 3432, -12012, 30030, 45045,
+		// C4[1], coeff of eps^5, polynomial in n of order 0
+		1, 9009,
+		// C4[1], coeff of eps^4, polynomial in n of order 1
+		-2944, 468, 135135,
+		// C4[1], coeff of eps^3, polynomial in n of order 2
+		5792, 1040, -1287, 135135,
+		// C4[1], coeff of eps^2, polynomial in n of order 3
+		5952, -11648, 9152, -2574, 135135,
+		// C4[1], coeff of eps^1, polynomial in n of order 4
+		-64, -624, 4576, -6864, 3003, 135135,
+		// C4[2], coeff of eps^5, polynomial in n of order 0
+		8, 10725,
+		// C4[2], coeff of eps^4, polynomial in n of order 1
+		1856, -936, 225225,
+		// C4[2], coeff of eps^3, polynomial in n of order 2
+		-8448, 4992, -1144, 225225,
+		// C4[2], coeff of eps^2, polynomial in n of order 3
+		-1440, 4160, -4576, 1716, 225225,
+		// C4[3], coeff of eps^5, polynomial in n of order 0
+		-136, 63063,
+		// C4[3], coeff of eps^4, polynomial in n of order 1
+		1024, -208, 105105,
+		// C4[3], coeff of eps^3, polynomial in n of order 2
+		3584, -3328, 1144, 315315,
+		// C4[4], coeff of eps^5, polynomial in n of order 0
+		-128, 135135,
+		// C4[4], coeff of eps^4, polynomial in n of order 1
+		-2560, 832, 405405,
+		// C4[5], coeff of eps^5, polynomial in n of order 0
+		128, 99099,
+	}
+	o, k := 0, 0
+	for l := 0; l < nC4; l++ { // l is index of C4[l]
+		for j := nC4 - 1; j >= l; j-- { // coeff of eps^j
+			m := nC4 - j - 1 // order of polynomial in n
+			g.C4x[k] = polyval(m, coeff[o:], g.n) / coeff[o+m+1]
+			k++
+			o += m + 2
+		}
+	}
+}
+
+// astroid solves k^4+2*k^3-(x^2+y^2-1)*k^2-2*y^2*k-y^2 = 0 for positive root k.
+// This solution is adapted from Geocentric::Reverse.
+func astroid(x, y float64) float64 {
+	p := x * x
+	q := y * y
+	r := (p + q - 1) / 6
+	if q == 0 && r <= 0 {
+		// y = 0 with |x| <= 1. Handle this case directly.
+		// for y small, positive root is k = abs(y)/sqrt(1-x^2)
+		return 0
+	}
+
+	// Avoid possible division by zero when r = 0 by multiplying equations
+	// for s and t by r^3 and r, resp.
+	S := p * q / 4 // S = r^3 * s
+	r2 := r * r
+	r3 := r * r2
+	// The discriminant of the quadratic equation for T3. This is zero on
+	// the evolute curve p^(1/3)+q^(1/3) = 1
+	disc := S * (S + 2*r3)
+	u := r
+	if disc >= 0 {
+		T3 := S + r3
+		// Pick the sign on the sqrt to maximize abs(T3). This minimizes loss
+		// of precision due to cancellation. The result is unchanged because
+		// of the way the T is used in definition of u.
+		if T3 < 0 {
+			T3 -= math.Sqrt(disc)
+		} else {
+			T3 += math.Sqrt(disc) // T3 = (r * t)^3
+		}
+		// N.B. cbrt always returns the real root. cbrt(-8) = -2.
+		T := math.Cbrt(T3) // T = r * t
+		// T can be zero; but then r2 / T -> 0.
+		u += T
+		if T != 0 {
+			u += r2 / T
+		}
+	} else {
+		// T is complex, but the way u is defined the result is real.
+		ang := math.Atan2(math.Sqrt(-disc), -(S + r3))
+		// There are three possible cube roots. We choose the root which
+		// avoids cancellation. Note that disc < 0 implies that r < 0.
+		u += 2 * r * math.Cos(ang/3)
+	}
+	v := math.Sqrt(u*u + q) // guaranteed positive
+	// Avoid loss of accuracy when u < 0.
+	uv := u + v // u+v, guaranteed positive
+	if u < 0 {
+		uv = q / (v - u)
+	}
+	w := (uv - q) / (2 * v) // positive?
+	// Rearrange expression for k to avoid loss of accuracy due to
+	// subtraction. Division by 0 not possible because uv > 0, w >= 0.
+	return uv / (math.Sqrt(uv+w*w) + w) // guaranteed positive
+}
+
+func polyval(n int, p []float64, x float64) float64 {
+	if n < 0 {
+		return 0
+	}
+	y := p[0]
+	for i := 1; i <= n; i++ {
+		y = y*x + p[i]
+	}
+	return y
+}
+
+// sinCosSeries evaluates
+// y = sinp ? sum(c[i] * sin( 2*i    * x), i, 1, n) :
+//
+//	sum(c[i] * cos((2*i+1) * x), i, 0, n-1)
+//
+// using Clenshaw summation. N.B. c[0] is unused for sin series.
+func sinCosSeries(sinp bool, sinx, cosx float64, c []float64, n int) float64 {
+	k := n // Point to one beyond last element
+	if sinp {
+		k++
+	}
+	ar := 2 * (cosx - sinx) * (cosx + sinx) // 2 * cos(2 * x)
+	var y0, y1 float64
+	if n&1 != 0 {
+		k--
+		y0 = c[k]
+	}
+	// Now n is even
+	for n /= 2; n > 0; n-- {
+		// Unroll loop x 2, so accumulators return to their original role
+		k--
+		y1 = ar*y0 - y1 + c[k]
+		k--
+		y0 = ar*y1 - y0 + c[k]
+	}
+	if sinp {
+		return 2 * sinx * cosx * y0 // sin(2 * x) * y0
+	}
+	return cosx * (y0 - y1) // cos(x) * (y0 - y1)
+}
+
+func norm2(sinx, cosx float64) (float64, float64) {
+	r := math.Hypot(sinx, cosx)
+	return sinx / r, cosx / r
+}
+
+// sumx is an error free transformation of a sum, t is the exact error.
+func sumx(u, v float64) (s, t float64) {
+	s = u + v
+	up := s - v
+	vpp := s - up
+	up -= u
+	vpp -= v
+	t = -(up + vpp)
+	return s, t
+}
+
+// angNormalize reduces an angle to (-180, 180].
+func angNormalize(x float64) float64 {
+	x = math.Remainder(x, 360)
+	if x == -180 {
+		return 180
+	}
+	return x
+}
+
+// angDiff computes y - x, reduced to (-180, 180], exactly, e receives the rounding error.
+func angDiff(x, y float64, e *float64) float64 {
+	d, t := sumx(angNormalize(-x), angNormalize(y))
+	d = angNormalize(d)
+	// Here y - x = d + t (mod 360), exactly, where d is in (-180,180] and
+	// abs(t) <= eps (eps = 2^-45 for doubles). The only case where the
+	// addition of t takes the result outside the range (-180,180] is d = 180
+	// and t > 0.
+	if d == 180 && t > 0 {
+		d = -180
+	}
+	d, t = sumx(d, t)
+	if e != nil {
+		*e = t
+	}
+	return d
+}
+
+func angRound(x float64) float64 {
+	const z = 1.0 / 16
+	if x == 0 {
+		return 0
+	}
+	y := math.Abs(x)
+	// The compiler mustn't "simplify" z - (z - y) to y
+	if y < z {
+		y = z - (z - y)
+	}
+	if x < 0 {
+		return -y
+	}
+	return y
+}
+
+func latFix(x float64) float64 {
+	if math.Abs(x) > 90 {
+		return math.NaN()
+	}
+	return x
+}
+
+// sincosd computes the sine and cosine of x degrees, exactly for multiples of 90.
+func sincosd(x float64) (sinx, cosx float64) {
+	r := math.Remainder(x, 90)
+	q := int(math.Round((x - r) / 90))
+	// now abs(r) <= 45
+	s, c := math.Sincos(r * degree)
+	switch q & 3 {
+	case 0:
+		sinx, cosx = s, c
+	case 1:
+		sinx, cosx = c, -s
+	case 2:
+		sinx, cosx = -s, -c
+	default:
+		sinx, cosx = -c, s
+	}
+	if x != 0 {
+		sinx += 0
+		cosx += 0
+	}
+	return sinx, cosx
+}
+
+// atan2d computes atan2(y, x) in degrees with the result in [-180, 180].
+func atan2d(y, x float64) float64 {
+	// In order to minimize round-off errors, this function rearranges the
+	// arguments so that result of atan2 is in the range [-pi/4, pi/4] before
+	// converting it to degrees and mapping the result to the correct quadrant.
+	q := 0
+	if math.Abs(y) > math.Abs(x) {
+		x, y = y, x
+		q = 2
+	}
+	if x < 0 || (x == 0 && math.Signbit(x)) {
+		x = -x
+		q++
+	}
+	// here x >= 0 and x >= abs(y), so angle is in [-pi/4, pi/4]
+	ang := math.Atan2(y, x) / degree
+	switch q {
+	case 1:
+		if y >= 0 {
+			ang = 180 - ang
+		} else {
+			ang = -180 - ang
+		}
+	case 2:
+		ang = 90 - ang
+	case 3:
+		ang = -90 + ang
+	}
+	return ang
+}
+
+// An accumulator keeps a sum as an unevaluated pair of doubles to give
+// about 106 bits of precision.
+type accumulator [2]float64
+
+func (s *accumulator) add(y float64) {
+	z, u := sumx(y, s[1])
+	s[0], s[1] = sumx(z, s[0])
+	if s[0] == 0 {
+		s[0] = u
+	} else {
+		s[1] += u
+	}
+}
+
+func (s accumulator) sum(y float64) float64 {
+	s.add(y)
+	return s[0]
+}
+
+func (s *accumulator) neg() {
+	s[0], s[1] = -s[0], -s[1]
+}
+
+func (s *accumulator) rem(y float64) {
+	s[0] = math.Remainder(s[0], y)
+	s.add(0)
+}
+
+// transit returns 1 or -1 if crossing prime meridian in east or west direction, otherwise zero.
+func transit(lon1, lon2 float64) int {
+	// Compute lon12 the same way as geodesic.inverse.
+	lon1 = angNormalize(lon1)
+	lon2 = angNormalize(lon2)
+	lon12 := angDiff(lon1, lon2, nil)
+	switch {
+	case lon1 <= 0 && lon2 > 0 && lon12 > 0:
+		return 1
+	case lon2 <= 0 && lon1 > 0 && lon12 < 0:
+		return -1
+	}
+	return 0
+}
+
+// areaReduce reduces the accumulated clockwise area of a closed polygon given the
+// area of the whole surface area0 and the number of prime meridian crossings.
+// The result is counter-clockwise positive and in (-area0/2, area0/2].
+func areaReduce(area accumulator, area0 float64, crossings int) float64 {
+	area.rem(area0)
+	if crossings&1 != 0 {
+		if area[0] < 0 {
+			area.add(area0 / 2)
+		} else {
+			area.add(-area0 / 2)
+		}
+	}
+	// area is with the clockwise sense, convert to counter-clockwise convention.
+	area.neg()
+	// put area in (-area0/2, area0/2]
+	if area[0] > area0/2 {
+		area.add(-area0)
+	} else if area[0] <= -area0/2 {
+		area.add(area0)
+	}
+	return 0 + area[0]
+}
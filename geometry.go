@@ -1,12 +1,17 @@
 // Package geojson
-// most code borrow from https://github.com/paulmach/go.geojson/blob/master/geometry.go, but this one is for mongodb BSON only, not JSON
+// most code borrow from https://github.com/paulmach/go.geojson/blob/master/geometry.go, but this one is for mongodb BSON first,
+// JSON is plain RFC 7946 GeoJSON
 package geojson
 
 import (
-	"errors"
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
 	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
@@ -24,6 +29,75 @@ const (
 	GeometryCollection      GeometryType = "GeometryCollection"
 )
 
+// IsKnown returns true when the type is one of the GeoJSON geometry types.
+func (t GeometryType) IsKnown() bool {
+	switch t {
+	case GeometryPoint, GeometryMultiPoint, GeometryLineString, GeometryMultiLineString,
+		GeometryPolygon, GeometryMultiPolygon, GeometryCollection:
+		return true
+	}
+	return false
+}
+
+// lookupGeometryType finds the GeoJSON geometry type matching s regardless of case.
+func lookupGeometryType(s string) (GeometryType, bool) {
+	for _, t := range []GeometryType{
+		GeometryPoint, GeometryMultiPoint, GeometryLineString, GeometryMultiLineString,
+		GeometryPolygon, GeometryMultiPolygon, GeometryCollection,
+	} {
+		if strings.EqualFold(string(t), s) {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// DecodeOptions controls how BSON documents are decoded into geometries.
+// The zero value is the strict decoding used by UnmarshalBSON.
+type DecodeOptions struct {
+	// Lenient accepts geometry types regardless of case, e.g. "point", and keeps
+	// geometries of an unrecognized type, e.g. "Circle", in Geometry.Raw instead of failing.
+	Lenient bool
+
+	// StrictPositions rejects the positions with fewer than two or more than three
+	// elements, e.g. [lon] or [lon, lat, alt, m], instead of decoding them as is.
+	StrictPositions bool
+
+	// Decimal128 accepts coordinates stored as Decimal128, e.g. by legacy applications,
+	// it fails for the values beyond the range or the precision of a float64.
+	Decimal128 bool
+
+	// NumericStrings accepts coordinates stored as strings holding a decimal number,
+	// e.g. "48.85" as imported from a CSV file.
+	NumericStrings bool
+
+	// MaxDepth limits how deeply GeometryCollections may be nested, a collection
+	// holding a collection is 2 levels deep. DefaultMaxDepth applies when it is 0.
+	MaxDepth int
+}
+
+// DefaultMaxDepth is the nesting limit of GeometryCollections when
+// DecodeOptions.MaxDepth is not set.
+const DefaultMaxDepth = 32
+
+// maxDepth returns the nesting limit of GeometryCollections.
+func (opts DecodeOptions) maxDepth() int {
+	if opts.MaxDepth > 0 {
+		return opts.MaxDepth
+	}
+
+	return DefaultMaxDepth
+}
+
+// EncodeOptions change how geometries are encoded to BSON by GeometryCodec and
+// MarshalGeometryWithOptions.
+type EncodeOptions struct {
+	// Decimal128 writes the coordinates as Decimal128 instead of doubles, for the
+	// systems that need exact decimal storage, with the fewest digits that decode
+	// back to the same float64, e.g. 48.85 rather than 48.850000000000001421.
+	Decimal128 bool
+}
+
 // A Geometry correlates to a GeoJSON geometry object.
 type Geometry struct {
 	Type GeometryType `bson:"type" json:"type"`
@@ -73,6 +147,18 @@ type Geometry struct {
 	MultiPolygon [][][]Point
 
 	Geometries []*Geometry
+
+	// BBox is the optional bbox member of the geometry, [west, south, east, north]
+	// or with altitudes [west, south, min altitude, east, north, max altitude].
+	// It is kept as is, use Bound to compute it from the coordinates.
+	BBox []float64
+
+	// CRS is the optional crs member of the geometry, see NewBigPolygon.
+	CRS *CRS
+
+	// Raw keeps the original document of a geometry whose type is not one of the
+	// GeoJSON geometry types, it is only set when decoding with DecodeOptions.Lenient.
+	Raw bson.Raw
 }
 
 // Point presents a geometry point, must in format []float64{longitude, latitude}
@@ -146,13 +232,17 @@ func NewGeometryCollection(geometries ...*Geometry) *Geometry {
 // defining a struct here lets us define the order of the BSON elements.
 type geometry struct {
 	Type        GeometryType `bson:"type" json:"type,omitempty"`
+	BBox        []float64    `bson:"bbox,omitempty" json:"bbox,omitempty"`
 	Coordinates interface{}  `bson:"coordinates,omitempty" json:"coordinates,omitempty"`
 	Geometries  interface{}  `bson:"geometries,omitempty" json:"geometries,omitempty"`
+	CRS         *CRS         `bson:"crs,omitempty" json:"crs,omitempty"`
 }
 
 func (g *Geometry) toPureGeometry() *geometry {
 	geo := &geometry{
 		Type: g.Type,
+		BBox: g.BBox,
+		CRS:  g.CRS,
 	}
 
 	switch g.Type {
@@ -178,20 +268,72 @@ func (g *Geometry) toPureGeometry() *geometry {
 // MarshalBSON implements bson.Marshaler
 // nolint: gocritic
 func (g Geometry) MarshalBSON() ([]byte, error) {
+	if !g.Type.IsKnown() && g.Raw != nil {
+		return g.Raw, nil
+	}
 	geo := g.toPureGeometry()
 	return bson.Marshal(geo)
 }
 
-// MarshalJSON for testing purpose
+// MarshalGeometryWithOptions encodes the geometry to binary BSON using the given
+// encode options.
+func MarshalGeometryWithOptions(g *Geometry, opts EncodeOptions) ([]byte, error) {
+	if g == nil {
+		return nil, ErrNilGeometry
+	}
+
+	var buf bytes.Buffer
+	vw, err := bsonrw.NewBSONValueWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = (geometryWriter{opts}).writeGeometry(vw, g); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// MarshalJSON converts the geometry object into RFC 7946 GeoJSON,
+// numbers are written in their shortest round-trip form, e.g. 1 rather than 1.0.
+// The Raw document of a geometry of an unknown type is converted to plain JSON.
+// It fails for NaN and infinite coordinates, e.g. the M-only positions of UnmarshalWKT.
+// MarshalJSON implements json.Marshaler
 // nolint: gocritic
 func (g Geometry) MarshalJSON() ([]byte, error) {
+	if !g.Type.IsKnown() && g.Raw != nil {
+		return rawDocumentJSON(g.Raw)
+	}
+	if err := checkJSONNumbers(&g); err != nil {
+		return nil, err
+	}
 	geo := g.toPureGeometry()
-	return bson.MarshalExtJSON(geo, false, false)
+	return json.Marshal(geo)
 }
 
+// MarshalExtJSON converts the geometry object into relaxed MongoDB Extended JSON,
+// the same document MarshalBSON produces.
+// nolint: gocritic
+func (g Geometry) MarshalExtJSON() ([]byte, error) {
+	return bson.MarshalExtJSON(g, false, false)
+}
+
+// UnmarshalGeometryRawJSON decodes RFC 7946 GeoJSON data into a GeoJSON geometry.
 func UnmarshalGeometryRawJSON(data []byte) (*Geometry, error) {
 	g := &Geometry{}
-	err := bson.UnmarshalExtJSON(data, true, g)
+	err := json.Unmarshal(data, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// UnmarshalGeometryExtJSON decodes MongoDB Extended JSON data into a GeoJSON geometry.
+func UnmarshalGeometryExtJSON(data []byte) (*Geometry, error) {
+	g := &Geometry{}
+	err := bson.UnmarshalExtJSON(data, false, g)
 	if err != nil {
 		return nil, err
 	}
@@ -211,9 +353,25 @@ func UnmarshalGeometry(data []byte) (*Geometry, error) {
 	return g, nil
 }
 
+// UnmarshalGeometryWithOptions decodes the binary BSON data into a GeoJSON geometry
+// using the given decode options.
+func UnmarshalGeometryWithOptions(data []byte, opts DecodeOptions) (*Geometry, error) {
+	g := &Geometry{}
+	err := g.unmarshalBSON(data, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
 // UnmarshalBSON decodes the data into a GeoJSON geometry.
 // This fulfills the bson.Unmarshaler interface.
 func (g *Geometry) UnmarshalBSON(data []byte) error {
+	return g.unmarshalBSON(data, DecodeOptions{})
+}
+
+func (g *Geometry) unmarshalBSON(data []byte, opts DecodeOptions) error {
 	if len(data) == 0 {
 		return nil
 	}
@@ -223,159 +381,236 @@ func (g *Geometry) UnmarshalBSON(data []byte) error {
 		return err
 	}
 
-	return decodeGeometry(g, object)
+	err = decodeGeometry(g, object, opts, 0)
+	if err == nil {
+		keepRawDocuments(g, data)
+	}
+
+	return err
 }
 
-// UnmarshalJSON decodes the data into a GeoJSON geometry.
+// keepRawDocuments replaces the Raw of g and of its members whose type is unknown
+// with a copy of their original document, data is the document of g.
+// decodeGeometry marshals the decoded map back, which loses the element order.
+func keepRawDocuments(g *Geometry, data bson.Raw) {
+	if g.Raw != nil {
+		g.Raw = append(bson.Raw(nil), data...)
+		return
+	}
+
+	for i, member := range g.Geometries {
+		if doc, ok := data.Lookup("geometries", strconv.Itoa(i)).DocumentOK(); ok && member != nil {
+			keepRawDocuments(member, doc)
+		}
+	}
+}
+
+// UnmarshalJSON decodes the RFC 7946 GeoJSON data into a GeoJSON geometry.
 // This fulfills the json.Unmarshaler interface.
 func (g *Geometry) UnmarshalJSON(data []byte) error {
-	err := bson.UnmarshalExtJSON(data, true, g)
-	if err != nil {
+	object, err := decodeJSONObject(data)
+	if err != nil || object == nil {
 		return err
 	}
 
-	return nil
+	return decodeGeometry(g, object, DecodeOptions{}, 0)
 }
 
-func decodeGeometry(g *Geometry, object map[string]interface{}) error {
-	t, ok := object["type"]
+// decodeGeometry decodes the geometry object, depth is the number of
+// GeometryCollections it is a member of.
+func decodeGeometry(g *Geometry, object map[string]interface{}, opts DecodeOptions, depth int) error {
+	g.Raw = nil
+
+	s, ok := object["type"].(string)
 	if !ok {
-		return errors.New("type property not defined")
+		return decodeErrorAt(decodeError("geometry type", object["type"]), "type")
 	}
 
-	if s, ok := t.(string); ok {
-		g.Type = GeometryType(s)
-	} else {
-		return errors.New("type property not string")
+	g.Type = GeometryType(s)
+	if !g.Type.IsKnown() {
+		if !opts.Lenient {
+			return &DecodeError{Path: "type", Err: fmt.Errorf("%w %q", ErrUnknownType, s)}
+		}
+		if known, ok := lookupGeometryType(s); ok {
+			g.Type = known
+		} else {
+			// the element order of a map is lost, see keepRawDocuments
+			raw, err := bson.Marshal(object)
+			if err != nil {
+				return err
+			}
+			g.Raw = raw
+			return nil
+		}
 	}
 
 	var err error
+	if g.BBox, err = decodeBBox(object["bbox"], opts); err != nil {
+		return decodeErrorAt(err, "bbox")
+	}
+	if g.CRS, err = decodeCRS(object["crs"]); err != nil {
+		return decodeErrorAt(err, "crs")
+	}
 
 	switch g.Type {
 	case GeometryPoint:
-		g.Point, err = decodePosition(object["coordinates"])
+		g.Point, err = decodePosition(object["coordinates"], opts)
 	case GeometryMultiPoint:
-		g.MultiPoint, err = decodePositionSet(object["coordinates"])
+		g.MultiPoint, err = decodePositionSet(object["coordinates"], opts)
 	case GeometryLineString:
-		g.LineString, err = decodePositionSet(object["coordinates"])
+		g.LineString, err = decodePositionSet(object["coordinates"], opts)
 	case GeometryMultiLineString:
-		g.MultiLineString, err = decodePathSet(object["coordinates"])
+		g.MultiLineString, err = decodePathSet(object["coordinates"], opts)
 	case GeometryPolygon:
-		g.Polygon, err = decodePathSet(object["coordinates"])
+		g.Polygon, err = decodePathSet(object["coordinates"], opts)
 	case GeometryMultiPolygon:
-		g.MultiPolygon, err = decodePolygonSet(object["coordinates"])
+		g.MultiPolygon, err = decodePolygonSet(object["coordinates"], opts)
 	case GeometryCollection:
-		g.Geometries, err = decodeGeometries(object["geometries"])
+		g.Geometries, err = decodeGeometries(object["geometries"], opts, depth+1)
+		return decodeErrorAt(err, "geometries")
 	}
 
-	return err
+	return decodeErrorAt(err, "coordinates")
 }
 
-func decodePosition(data interface{}) (Point, error) {
+func decodePosition(data interface{}, opts DecodeOptions) (Point, error) {
 	coords, ok := data.(primitive.A)
 	if !ok {
-		return nil, fmt.Errorf("not a valid position, got %v", data)
+		return nil, decodeError("position", data)
 	}
 
 	result := make(Point, 0, len(coords))
-	for _, coord := range coords {
-		switch f := coord.(type) {
-		case float64:
-			result = append(result, f)
-		case int:
-			result = append(result, float64(f))
-		case int32:
-			result = append(result, float64(f))
-		case int64:
-			result = append(result, float64(f))
-		default:
-			return nil, fmt.Errorf("not a valid coordinate, got %v", coord)
+	for i, coord := range coords {
+		f, err := decodeCoordinate(coord, opts)
+		if err != nil {
+			return nil, decodeErrorAtIndex(err, i)
 		}
+		result = append(result, f)
+	}
+
+	if opts.StrictPositions && !validPositionLength(result) {
+		return nil, &DecodeError{Err: fmt.Errorf("position must have 2 or 3 elements, got %d", len(result))}
 	}
 
 	return result, nil
 }
 
-func decodePositionSet(data interface{}) ([]Point, error) {
+func decodeCoordinate(data interface{}, opts DecodeOptions) (float64, error) {
+	var err error
+	switch f := data.(type) {
+	case float64:
+		return f, nil
+	case int:
+		return float64(f), nil
+	case int32:
+		return float64(f), nil
+	case int64:
+		return float64(f), nil
+	case primitive.Decimal128:
+		if opts.Decimal128 {
+			var c float64
+			if c, err = decimalCoordinate(f); err == nil {
+				return c, nil
+			}
+		}
+	case string:
+		if opts.NumericStrings {
+			var c float64
+			if c, err = stringCoordinate(f); err == nil {
+				return c, nil
+			}
+		}
+	}
+
+	return 0, &DecodeError{Expected: "coordinate", Actual: bsonTypeOf(data), Err: err}
+}
+
+func decodePositionSet(data interface{}, opts DecodeOptions) ([]Point, error) {
 	points, ok := data.(primitive.A)
 	if !ok {
-		return nil, fmt.Errorf("not a valid set of positions, got %v", data)
+		return nil, decodeError("set of positions", data)
 	}
 
 	result := make([]Point, 0, len(points))
-	for _, point := range points {
-		if p, err := decodePosition(point); err == nil {
-			result = append(result, p)
-		} else {
-			return nil, err
+	for i, point := range points {
+		p, err := decodePosition(point, opts)
+		if err != nil {
+			return nil, decodeErrorAtIndex(err, i)
 		}
+		result = append(result, p)
 	}
 
 	return result, nil
 }
 
-func decodePathSet(data interface{}) ([][]Point, error) {
+func decodePathSet(data interface{}, opts DecodeOptions) ([][]Point, error) {
 	sets, ok := data.(primitive.A)
 	if !ok {
-		return nil, fmt.Errorf("not a valid path, got %v", data)
+		return nil, decodeError("set of paths", data)
 	}
 
 	result := make([][]Point, 0, len(sets))
-
-	for _, set := range sets {
-		if s, err := decodePositionSet(set); err == nil {
-			result = append(result, s)
-		} else {
-			return nil, err
+	for i, set := range sets {
+		s, err := decodePositionSet(set, opts)
+		if err != nil {
+			return nil, decodeErrorAtIndex(err, i)
 		}
+		result = append(result, s)
 	}
 
 	return result, nil
 }
 
-func decodePolygonSet(data interface{}) ([][][]Point, error) {
+func decodePolygonSet(data interface{}, opts DecodeOptions) ([][][]Point, error) {
 	polygons, ok := data.(primitive.A)
 	if !ok {
-		return nil, fmt.Errorf("not a valid polygon, got %v", data)
+		return nil, decodeError("set of polygons", data)
 	}
 
 	result := make([][][]Point, 0, len(polygons))
-	for _, polygon := range polygons {
-		if p, err := decodePathSet(polygon); err == nil {
-			result = append(result, p)
-		} else {
-			return nil, err
+	for i, polygon := range polygons {
+		p, err := decodePathSet(polygon, opts)
+		if err != nil {
+			return nil, decodeErrorAtIndex(err, i)
 		}
+		result = append(result, p)
 	}
 
 	return result, nil
 }
 
-func decodeGeometries(data interface{}) ([]*Geometry, error) {
-	if vs, ok := data.(primitive.A); ok {
-		geometries := make([]*Geometry, 0, len(vs))
-		for _, v := range vs {
-			g := &Geometry{}
-
-			vmap, ok := v.(map[string]interface{})
-			if !ok {
-				break
-			}
+// decodeGeometries decodes the members of a GeometryCollection nested depth
+// levels deep, whichever document type the driver decoded them to.
+func decodeGeometries(data interface{}, opts DecodeOptions, depth int) ([]*Geometry, error) {
+	var vs []interface{}
+	switch data := data.(type) {
+	case primitive.A:
+		vs = data
+	case []interface{}:
+		vs = data
+	default:
+		return nil, decodeError("set of geometries", data)
+	}
 
-			err := decodeGeometry(g, vmap)
-			if err != nil {
-				return nil, err
-			}
+	if depth > opts.maxDepth() {
+		return nil, &DecodeError{Err: fmt.Errorf("%w, more than %d levels", ErrMaxDepth, opts.maxDepth())}
+	}
 
-			geometries = append(geometries, g)
+	geometries := make([]*Geometry, 0, len(vs))
+	for i, v := range vs {
+		object, ok := documentMap(v)
+		if !ok {
+			return nil, decodeErrorAtIndex(decodeError("geometry", v), i)
 		}
 
-		if len(geometries) == len(vs) {
-			return geometries, nil
+		g := &Geometry{}
+		if err := decodeGeometry(g, object, opts, depth); err != nil {
+			return nil, decodeErrorAtIndex(err, i)
 		}
+		geometries = append(geometries, g)
 	}
 
-	return nil, fmt.Errorf("not a valid set of geometries, got %v", data)
+	return geometries, nil
 }
 
 // IsPoint returns true with the geometry object is a Point type.
@@ -3,7 +3,12 @@ package geojson
 import (
 	"bytes"
 	"encoding/json"
+	"math"
+	"strings"
 	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestGeometryMarshalJSONPoint_JSON(t *testing.T) {
@@ -18,7 +23,7 @@ func TestGeometryMarshalJSONPoint_JSON(t *testing.T) {
 		t.Errorf("json should have type Point")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[1.0,2.0]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[1,2]`)) {
 		t.Errorf("json should marshal coordinates correctly, blob=%s", blob)
 	}
 }
@@ -35,7 +40,7 @@ func TestGeometryMarshalPoint_JSON(t *testing.T) {
 		t.Errorf("json should have type Point")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[1.0,2.0]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[1,2]`)) {
 		t.Errorf("json should marshal coordinates correctly")
 	}
 }
@@ -52,7 +57,7 @@ func TestGeometryMarshalPointValue_JSON(t *testing.T) {
 		t.Errorf("json should have type Point")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[1.0,2.0]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[1,2]`)) {
 		t.Errorf("json should marshal coordinates correctly")
 	}
 }
@@ -69,7 +74,7 @@ func TestGeometryMarshalJSONMultiPoint_JSON(t *testing.T) {
 		t.Errorf("json should have type MultiPoint")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[[1.0,2.0],[3.0,4.0]]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[[1,2],[3,4]]`)) {
 		t.Errorf("json should marshal coordinates correctly")
 	}
 }
@@ -86,7 +91,7 @@ func TestGeometryMarshalJSONLineString_JSON(t *testing.T) {
 		t.Errorf("json should have type LineString")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[[1.0,2.0],[3.0,4.0]]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[[1,2],[3,4]]`)) {
 		t.Errorf("json should marshal coordinates correctly")
 	}
 }
@@ -106,7 +111,7 @@ func TestGeometryMarshalJSONMultiLineString_JSON(t *testing.T) {
 		t.Errorf("json should have type MultiLineString")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[[[1.0,2.0],[3.0,4.0]],[[5.0,6.0],[7.0,8.0]]]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[[[1,2],[3,4]],[[5,6],[7,8]]]`)) {
 		t.Errorf("json should marshal coordinates correctly")
 	}
 }
@@ -126,7 +131,7 @@ func TestGeometryMarshalJSONPolygon_JSON(t *testing.T) {
 		t.Errorf("json should have type Polygon")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[[[1.0,2.0],[3.0,4.0]],[[5.0,6.0],[7.0,8.0]]]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[[[1,2],[3,4]],[[5,6],[7,8]]]`)) {
 		t.Errorf("json should marshal coordinates correctly")
 	}
 }
@@ -151,7 +156,7 @@ func TestGeometryMarshalJSONMultiPolygon_JSON(t *testing.T) {
 		t.Errorf("json should have type MultiPolygon")
 	}
 
-	if !bytes.Contains(blob, []byte(`"coordinates":[[[[1.0,2.0],[3.0,4.0]],[[5.0,6.0],[7.0,8.0]]],[[[8.0,7.0],[6.0,5.0]],[[4.0,3.0],[2.0,1.0]]]]`)) {
+	if !bytes.Contains(blob, []byte(`"coordinates":[[[[1,2],[3,4]],[[5,6],[7,8]]],[[[8,7],[6,5]],[[4,3],[2,1]]]]`)) {
 		t.Errorf("json should marshal coordinates correctly")
 	}
 }
@@ -257,3 +262,107 @@ func TestUnmarshalGeometryMultiPolygon_JSON(t *testing.T) {
 		t.Errorf("should have 2 polygons but got %d", len(g.MultiPolygon))
 	}
 }
+
+func TestGeometryJSONRoundTrip_JSON(t *testing.T) {
+	rawJSON := `{"type":"GeometryCollection","geometries":[{"type":"Point","coordinates":[0.1,-1e-7]},{"type":"LineString","coordinates":[[1,2],[3.25,4]]}]}`
+
+	var doc struct {
+		Location *Geometry `json:"location"`
+	}
+	if err := json.Unmarshal([]byte(`{"location":`+rawJSON+`}`), &doc); err != nil {
+		t.Fatalf("should json.Unmarshal just fine but got %v", err)
+	}
+
+	if doc.Location == nil || len(doc.Location.Geometries) != 2 {
+		t.Fatalf("incorrect geometry, got %+v", doc.Location)
+	}
+
+	blob, err := json.Marshal(doc.Location)
+	if err != nil {
+		t.Fatalf("should json.Marshal just fine but got %v", err)
+	}
+
+	if string(blob) != rawJSON {
+		t.Errorf("json should round trip\n got %s\nwant %s", blob, rawJSON)
+	}
+}
+
+func TestGeometryMarshalExtJSON_JSON(t *testing.T) {
+	g := NewPoint([]float64{1, 2})
+	blob, err := g.MarshalExtJSON()
+	if err != nil {
+		t.Fatalf("should marshal to extended json just fine but got %v", err)
+	}
+
+	if !bytes.Contains(blob, []byte(`"coordinates":[1.0,2.0]`)) {
+		t.Errorf("extended json should marshal coordinates as doubles, blob=%s", blob)
+	}
+
+	decoded, err := UnmarshalGeometryExtJSON([]byte(`{"type":"Point","coordinates":[{"$numberDouble":"1.0"},{"$numberInt":"2"}]}`))
+	if err != nil {
+		t.Fatalf("should unmarshal extended json without issue, err %v", err)
+	}
+
+	if len(decoded.Point) != 2 || decoded.Point[1] != 2 {
+		t.Errorf("incorrect coordinates, got %v", decoded.Point)
+	}
+}
+
+func TestUnmarshalGeometryInvalid_JSON(t *testing.T) {
+	for _, rawJSON := range []string{
+		`{"type": "Point", "coordinates": ["1", 2]}`,
+		`{"coordinates": [1, 2]}`,
+		`[1, 2]`,
+	} {
+		if _, err := UnmarshalGeometryRawJSON([]byte(rawJSON)); err == nil {
+			t.Errorf("should fail to unmarshal %s", rawJSON)
+		}
+	}
+}
+
+func TestGeometryMarshalJSONRaw_JSON(t *testing.T) {
+	id, _ := primitive.ObjectIDFromHex("5f1d7b3c9d4e2a0001a1b2c3")
+	decimal, _ := primitive.ParseDecimal128("48.85")
+	data, _ := bson.Marshal(bson.D{
+		{Key: "type", Value: "Circle"},
+		{Key: "center", Value: bson.A{decimal, int32(2)}},
+		{Key: "radius", Value: 10.5},
+		{Key: "owner", Value: id},
+	})
+
+	g, err := UnmarshalGeometryWithOptions(data, DecodeOptions{Lenient: true})
+	if err != nil {
+		t.Fatalf("should unmarshal geometry without issue, err %v", err)
+	}
+
+	blob, err := json.Marshal(g)
+	if err != nil {
+		t.Fatalf("should marshal to json just fine but got %v", err)
+	}
+
+	want := `{"type":"Circle","center":[48.85,2],"radius":10.5,"owner":"5f1d7b3c9d4e2a0001a1b2c3"}`
+	if string(blob) != want {
+		t.Errorf("raw geometry should marshal to plain json\n got %s\nwant %s", blob, want)
+	}
+
+	g.Raw, _ = bson.Marshal(bson.D{{Key: "type", Value: "Circle"}, {Key: "data", Value: primitive.Binary{Data: []byte{1}}}})
+	if _, err := json.Marshal(g); err == nil {
+		t.Errorf("should fail to marshal binary data to json")
+	}
+}
+
+func TestGeometryMarshalJSONNaN_JSON(t *testing.T) {
+	g, err := UnmarshalWKT("LINESTRING M (1 2 3, 4 5 6)")
+	if err != nil {
+		t.Fatalf("should unmarshal wkt without issue, err %v", err)
+	}
+
+	_, err = json.Marshal(g)
+	if err == nil || !strings.Contains(err.Error(), "NaN") {
+		t.Errorf("should fail to marshal a NaN coordinate with a clear error, got %v", err)
+	}
+
+	if _, err := json.Marshal(NewFeature(NewPoint(Point{math.Inf(1), 2}))); err == nil {
+		t.Errorf("should fail to marshal an infinite coordinate")
+	}
+}
@@ -2,14 +2,16 @@ package geojson
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/primitive"
 )
 
 func TestGeometryMarshalBSONPoint(t *testing.T) {
 	g := NewPoint(Point{1, 2})
-	blob, err := g.MarshalJSON()
+	blob, err := g.MarshalExtJSON()
 	if err != nil {
 		t.Fatalf("should marshal to bson just fine but got %v", err)
 	}
@@ -57,7 +59,7 @@ func TestGeometryMarshalPointValue(t *testing.T) {
 
 func TestGeometryMarshalBSONMultiPoint(t *testing.T) {
 	g := NewMultiPoint(Point{1, 2}, Point{3, 4})
-	blob, err := g.MarshalJSON()
+	blob, err := g.MarshalExtJSON()
 	if err != nil {
 		t.Fatalf("should marshal to bson just fine but got %v", err)
 	}
@@ -73,7 +75,7 @@ func TestGeometryMarshalBSONMultiPoint(t *testing.T) {
 
 func TestGeometryMarshalBSONLineString(t *testing.T) {
 	g := NewLineString([]Point{{1, 2}, {3, 4}})
-	blob, err := g.MarshalJSON()
+	blob, err := g.MarshalExtJSON()
 	if err != nil {
 		t.Fatalf("should marshal to bson just fine but got %v", err)
 	}
@@ -92,7 +94,7 @@ func TestGeometryMarshalBSONMultiLineString(t *testing.T) {
 		[]Point{{1, 2}, {3, 4}},
 		[]Point{{5, 6}, {7, 8}},
 	)
-	blob, err := g.MarshalJSON()
+	blob, err := g.MarshalExtJSON()
 	if err != nil {
 		t.Fatalf("should marshal to bson just fine but got %v", err)
 	}
@@ -111,7 +113,7 @@ func TestGeometryMarshalBSONPolygon(t *testing.T) {
 		{{1, 2}, {3, 4}},
 		{{5, 6}, {7, 8}},
 	})
-	blob, err := g.MarshalJSON()
+	blob, err := g.MarshalExtJSON()
 	if err != nil {
 		t.Fatalf("should marshal to bson just fine but got %v", err)
 	}
@@ -135,7 +137,7 @@ func TestGeometryMarshalBSONMultiPolygon(t *testing.T) {
 			{{8, 7}, {6, 5}},
 			{{4, 3}, {2, 1}},
 		})
-	blob, err := g.MarshalJSON()
+	blob, err := g.MarshalExtJSON()
 	if err != nil {
 		t.Fatalf("should marshal to bson just fine but got %v", err)
 	}
@@ -154,7 +156,7 @@ func TestGeometryMarshalBSONCollection(t *testing.T) {
 		NewPoint(Point{1, 2}),
 		NewMultiPoint(Point{1, 2}, Point{3, 4}),
 	)
-	blob, err := g.MarshalJSON()
+	blob, err := g.MarshalExtJSON()
 	if err != nil {
 		t.Fatalf("should marshal to bson just fine but got %v", err)
 	}
@@ -306,3 +308,155 @@ func TestUnmarshalGeometryCollection(t *testing.T) {
 		t.Errorf("should have 2 geometries but got %d", len(g.Geometries))
 	}
 }
+
+func TestDecodeGeometriesMembers(t *testing.T) {
+	raw, _ := bson.Marshal(bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{7, 8}}})
+	members := primitive.A{
+		map[string]interface{}{"type": "Point", "coordinates": primitive.A{1, 2}},
+		primitive.M{"type": "Point", "coordinates": primitive.A{3, 4}},
+		primitive.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: primitive.A{5, 6}}},
+		bson.Raw(raw),
+	}
+
+	geometries, err := decodeGeometries(members, DecodeOptions{}, 1)
+	if err != nil {
+		t.Fatalf("should decode every member just fine but got %v", err)
+	}
+
+	if len(geometries) != 4 {
+		t.Fatalf("should have 4 geometries but got %d", len(geometries))
+	}
+
+	for i, g := range geometries {
+		if g.Type != GeometryPoint || g.Point[0] != float64(2*i+1) {
+			t.Errorf("incorrect member %d, got %+v", i, g)
+		}
+	}
+
+	_, err = decodeGeometries(append(members, "Point", members[0]), DecodeOptions{}, 1)
+	var e *DecodeError
+	if !errors.As(err, &e) || e.Path != "[4]" || e.Expected != "geometry" {
+		t.Errorf("should report the member that is not a document, got %v", err)
+	}
+}
+
+func TestDecodeGeometriesMaxDepth(t *testing.T) {
+	nested := func(depth int) bson.D {
+		g := bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1, 2}}}
+		for i := 0; i < depth; i++ {
+			g = bson.D{{Key: "type", Value: "GeometryCollection"}, {Key: "geometries", Value: bson.A{g}}}
+		}
+		return g
+	}
+
+	decoders := map[string]func(data []byte, opts DecodeOptions) error{
+		"bson": func(data []byte, opts DecodeOptions) error {
+			_, err := UnmarshalGeometryWithOptions(data, opts)
+			return err
+		},
+		"codec": func(data []byte, opts DecodeOptions) error {
+			doc, _ := bson.Marshal(bson.D{{Key: "geometry", Value: bson.Raw(data)}})
+			registry := RegisterGeometryCodec(bson.NewRegistryBuilder()).
+				RegisterTypeDecoder(tGeometryPtr, NewGeometryCodec(opts)).
+				Build()
+			return bson.UnmarshalWithRegistry(registry, doc, &codecDocument{})
+		},
+	}
+
+	for name, decode := range decoders {
+		data, _ := bson.Marshal(nested(DefaultMaxDepth))
+		if err := decode(data, DecodeOptions{}); err != nil {
+			t.Errorf("%s: should decode %d levels just fine but got %v", name, DefaultMaxDepth, err)
+		}
+
+		data, _ = bson.Marshal(nested(DefaultMaxDepth + 1))
+		if err := decode(data, DecodeOptions{}); !errors.Is(err, ErrMaxDepth) {
+			t.Errorf("%s: should fail past the default depth but got %v", name, err)
+		}
+
+		data, _ = bson.Marshal(nested(3))
+		err := decode(data, DecodeOptions{MaxDepth: 2})
+		var e *DecodeError
+		if !errors.As(err, &e) || !errors.Is(err, ErrMaxDepth) {
+			t.Fatalf("%s: should fail past the configured depth but got %v", name, err)
+		}
+		if e.Path != "geometries[0].geometries[0].geometries" {
+			t.Errorf("%s: incorrect path, got %q", name, e.Path)
+		}
+	}
+
+	data, _ := bson.Marshal(nested(DefaultMaxDepth + 1))
+	if _, err := RawGeometry(data).NumPositions(); !errors.Is(err, ErrMaxDepth) {
+		t.Errorf("raw: should fail past the default depth but got %v", err)
+	}
+}
+
+func TestUnmarshalGeometryUnknownType(t *testing.T) {
+	for _, rawJSON := range []string{
+		`{"type": "Circle", "coordinates": [1, 2], "radius": 10}`,
+		`{"type": "point", "coordinates": [1, 2]}`,
+	} {
+		_, err := UnmarshalGeometryRawJSON([]byte(rawJSON))
+		if err == nil {
+			t.Fatalf("should fail to unmarshal unknown type %s", rawJSON)
+		}
+	}
+}
+
+func TestUnmarshalGeometryWithOptionsLenient(t *testing.T) {
+	opts := DecodeOptions{Lenient: true}
+
+	data, _ := bson.Marshal(bson.D{{Key: "type", Value: "point"}, {Key: "coordinates", Value: bson.A{1, 2}}})
+	g, err := UnmarshalGeometryWithOptions(data, opts)
+	if err != nil {
+		t.Fatalf("should unmarshal geometry without issue, err %v", err)
+	}
+
+	if g.Type != GeometryPoint || len(g.Point) != 2 {
+		t.Errorf("incorrect geometry, got %+v", g)
+	}
+
+	data, _ = bson.Marshal(bson.D{{Key: "type", Value: "Circle"}, {Key: "radius", Value: 10}})
+	g, err = UnmarshalGeometryWithOptions(data, opts)
+	if err != nil {
+		t.Fatalf("should unmarshal geometry without issue, err %v", err)
+	}
+
+	if g.Type != "Circle" || g.Raw.Lookup("radius").Int32() != 10 {
+		t.Errorf("unknown geometry should be kept as raw bson, got %+v", g)
+	}
+
+	blob, err := bson.Marshal(g)
+	if err != nil {
+		t.Fatalf("should marshal to bson just fine but got %v", err)
+	}
+
+	if !bytes.Equal(blob, data) {
+		t.Errorf("raw geometry should marshal back unchanged")
+	}
+
+	data, _ = bson.Marshal(bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1, 2}}})
+	if err = g.unmarshalBSON(data, opts); err != nil {
+		t.Fatalf("should unmarshal geometry without issue, err %v", err)
+	}
+
+	if g.Type != GeometryPoint || g.Raw != nil {
+		t.Errorf("should not keep the raw document of a previous geometry, got %+v", g)
+	}
+
+	circle := bson.D{{Key: "type", Value: "Circle"}, {Key: "radius", Value: 10}, {Key: "center", Value: bson.A{1, 2}}, {Key: "area", Value: 314.15}}
+	member, _ := bson.Marshal(circle)
+	data, _ = bson.Marshal(bson.D{{Key: "type", Value: "GeometryCollection"}, {Key: "geometries", Value: bson.A{
+		bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1, 2}}},
+		bson.D{{Key: "type", Value: "GeometryCollection"}, {Key: "geometries", Value: bson.A{circle}}},
+	}}})
+
+	g, err = UnmarshalGeometryWithOptions(data, opts)
+	if err != nil {
+		t.Fatalf("should unmarshal geometry without issue, err %v", err)
+	}
+
+	if g.Geometries[0].Raw != nil || !bytes.Equal(g.Geometries[1].Geometries[0].Raw, member) {
+		t.Errorf("nested unknown geometry should keep its original document, got %v", g.Geometries[1].Geometries[0].Raw)
+	}
+}
@@ -0,0 +1,202 @@
+package geojson
+
+import (
+	"fmt"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+// GeoNearStage builds a $geoNear aggregation pipeline stage.
+// https://docs.mongodb.com/v4.2/reference/operator/aggregation/geoNear/
+type GeoNearStage struct {
+	// Near is the point for which to find the closest documents.
+	Near Point
+
+	// DistanceField is the output field that contains the calculated distance,
+	// use dot notation to specify a field within an embedded document.
+	DistanceField string
+
+	// Spherical determines how MongoDB calculates the distance between two points,
+	// true uses spherical geometry and requires a 2dsphere index, false uses planar
+	// geometry on a 2d index and writes Near as a legacy coordinate pair.
+	Spherical bool
+
+	// Key is the geospatial indexed field to use, required when the collection has
+	// more than one 2dsphere or 2d index.
+	Key string
+
+	// Query limits the results to the documents that match the query.
+	Query interface{}
+
+	// DistanceMultiplier is the factor to multiply all distances returned by the query, 0 leaves it out.
+	DistanceMultiplier float64
+
+	// IncludeLocs is the output field that identifies the location used to calculate the distance.
+	IncludeLocs string
+
+	// MinDistance and MaxDistance are distances in meters, or in coordinate units
+	// when Spherical is false, a value <= 0 leaves the bound out.
+	MinDistance float64
+	MaxDistance float64
+}
+
+// NewGeoNearStage creates a $geoNear stage near the given point which
+// writes the calculated distance to distanceField.
+func NewGeoNearStage(near Point, distanceField string) *GeoNearStage {
+	return &GeoNearStage{
+		Near:          near,
+		DistanceField: distanceField,
+		Spherical:     true,
+	}
+}
+
+// SetSpherical sets whether distances are calculated using spherical geometry.
+func (s *GeoNearStage) SetSpherical(spherical bool) *GeoNearStage {
+	s.Spherical = spherical
+	return s
+}
+
+// SetKey sets the geospatial indexed field to use.
+func (s *GeoNearStage) SetKey(key string) *GeoNearStage {
+	s.Key = key
+	return s
+}
+
+// SetQuery sets the query documents must match.
+func (s *GeoNearStage) SetQuery(query interface{}) *GeoNearStage {
+	s.Query = query
+	return s
+}
+
+// SetDistanceMultiplier sets the factor to multiply all distances by.
+func (s *GeoNearStage) SetDistanceMultiplier(multiplier float64) *GeoNearStage {
+	s.DistanceMultiplier = multiplier
+	return s
+}
+
+// SetIncludeLocs sets the output field for the location used to calculate the distance.
+func (s *GeoNearStage) SetIncludeLocs(field string) *GeoNearStage {
+	s.IncludeLocs = field
+	return s
+}
+
+// SetMinDistance sets the minimum distance, see MinDistance.
+func (s *GeoNearStage) SetMinDistance(min float64) *GeoNearStage {
+	s.MinDistance = min
+	return s
+}
+
+// SetMaxDistance sets the maximum distance, see MaxDistance.
+func (s *GeoNearStage) SetMaxDistance(max float64) *GeoNearStage {
+	s.MaxDistance = max
+	return s
+}
+
+// Stage returns the pipeline stage, it can be appended to a mongo.Pipeline.
+//
+//	{ $geoNear: { near: <point>, distanceField: <field>, spherical: true, ... } }
+func (s *GeoNearStage) Stage() bson.D {
+	var near interface{} = NewPoint(s.Near)
+	if !s.Spherical {
+		near = NewLegacyPoint(s.Near.Lon(), s.Near.Lat())
+	}
+
+	spec := bson.D{
+		{Key: "near", Value: near},
+		{Key: "distanceField", Value: s.DistanceField},
+		{Key: "spherical", Value: s.Spherical},
+	}
+	if s.Key != "" {
+		spec = append(spec, bson.E{Key: "key", Value: s.Key})
+	}
+	if s.Query != nil {
+		spec = append(spec, bson.E{Key: "query", Value: s.Query})
+	}
+	if s.DistanceMultiplier != 0 {
+		spec = append(spec, bson.E{Key: "distanceMultiplier", Value: s.DistanceMultiplier})
+	}
+	if s.IncludeLocs != "" {
+		spec = append(spec, bson.E{Key: "includeLocs", Value: s.IncludeLocs})
+	}
+	if s.MinDistance > 0 {
+		spec = append(spec, bson.E{Key: "minDistance", Value: s.MinDistance})
+	}
+	if s.MaxDistance > 0 {
+		spec = append(spec, bson.E{Key: "maxDistance", Value: s.MaxDistance})
+	}
+
+	return bson.D{{Key: "$geoNear", Value: spec}}
+}
+
+// GeoNearResult is a document returned by a $geoNear stage with its
+// distance and location fields decoded.
+type GeoNearResult[T any] struct {
+	// Document is the whole result document.
+	Document T
+
+	// Distance is the value of the stage DistanceField.
+	Distance float64
+
+	// Location is the value of the stage IncludeLocs field, nil when not requested.
+	Location *Geometry
+}
+
+// DecodeGeoNearResult decodes a document returned by the $geoNear stage s,
+// such as the current document of a mongo.Cursor.
+func DecodeGeoNearResult[T any](s *GeoNearStage, raw bson.Raw) (*GeoNearResult[T], error) {
+	result := &GeoNearResult[T]{}
+	if err := bson.Unmarshal(raw, &result.Document); err != nil {
+		return nil, err
+	}
+
+	val, err := raw.LookupErr(strings.Split(s.DistanceField, ".")...)
+	if err != nil {
+		return nil, fmt.Errorf("distance field %s not found: %w", s.DistanceField, err)
+	}
+	switch val.Type {
+	case bsontype.Double:
+		result.Distance = val.Double()
+	case bsontype.Int32, bsontype.Int64:
+		result.Distance = float64(val.AsInt64())
+	default:
+		return nil, fmt.Errorf("distance field %s not a number, got %v", s.DistanceField, val.Type)
+	}
+
+	if s.IncludeLocs == "" {
+		return result, nil
+	}
+
+	val, err = raw.LookupErr(strings.Split(s.IncludeLocs, ".")...)
+	if err != nil {
+		return nil, fmt.Errorf("location field %s not found: %w", s.IncludeLocs, err)
+	}
+	switch val.Type {
+	case bsontype.EmbeddedDocument:
+		if _, err := val.Document().LookupErr("type"); err != nil {
+			// legacy embedded document
+			p, err := UnmarshalLegacyPoint(val)
+			if err != nil {
+				return nil, err
+			}
+			result.Location = p.Geometry()
+			break
+		}
+		result.Location = &Geometry{}
+		if err := result.Location.UnmarshalBSON(val.Value); err != nil {
+			return nil, err
+		}
+	case bsontype.Array:
+		// legacy coordinate pair
+		p, err := UnmarshalLegacyPoint(val)
+		if err != nil {
+			return nil, err
+		}
+		result.Location = p.Geometry()
+	default:
+		return nil, fmt.Errorf("location field %s not a geometry, got %v", s.IncludeLocs, val.Type)
+	}
+
+	return result, nil
+}
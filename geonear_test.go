@@ -0,0 +1,106 @@
+package geojson
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestGeoNearStage(t *testing.T) {
+	stage := NewGeoNearStage(Point{-73.99279, 40.719296}, "dist.calculated").
+		SetKey("location").
+		SetQuery(bson.D{{Key: "category", Value: "Parks"}}).
+		SetIncludeLocs("dist.location").
+		SetMaxDistance(2000)
+
+	blob, err := bson.MarshalExtJSON(stage.Stage(), false, false)
+	if err != nil {
+		t.Fatalf("should marshal stage just fine but got %v", err)
+	}
+
+	want := `{"$geoNear":{"near":{"type":"Point","coordinates":[-73.99279,40.719296]},"distanceField":"dist.calculated",` +
+		`"spherical":true,"key":"location","query":{"category":"Parks"},"includeLocs":"dist.location","maxDistance":2000.0}}`
+	if string(blob) != want {
+		t.Errorf("incorrect stage\n got %s\nwant %s", blob, want)
+	}
+
+	stage = NewGeoNearStage(Point{-73.99279, 40.719296}, "dist").
+		SetSpherical(false).
+		SetMaxDistance(0.5)
+
+	blob, err = bson.MarshalExtJSON(stage.Stage(), false, false)
+	if err != nil {
+		t.Fatalf("should marshal 2d stage just fine but got %v", err)
+	}
+
+	want = `{"$geoNear":{"near":[-73.99279,40.719296],"distanceField":"dist","spherical":false,"maxDistance":0.5}}`
+	if string(blob) != want {
+		t.Errorf("incorrect 2d stage\n got %s\nwant %s", blob, want)
+	}
+}
+
+func TestDecodeGeoNearResult(t *testing.T) {
+	type place struct {
+		Name string `bson:"name"`
+	}
+
+	stage := NewGeoNearStage(Point{1, 2}, "dist.calculated").SetIncludeLocs("dist.location")
+	raw, err := bson.Marshal(bson.D{
+		{Key: "name", Value: "Sara D. Roosevelt Park"},
+		{Key: "dist", Value: bson.D{
+			{Key: "calculated", Value: 1147.42},
+			{Key: "location", Value: NewPoint(Point{-73.9927, 40.7194})},
+		}},
+	})
+	if err != nil {
+		t.Fatalf("should marshal document just fine but got %v", err)
+	}
+
+	result, err := DecodeGeoNearResult[place](stage, raw)
+	if err != nil {
+		t.Fatalf("should decode result without issue, err %v", err)
+	}
+
+	if result.Document.Name != "Sara D. Roosevelt Park" {
+		t.Errorf("incorrect document, got %+v", result.Document)
+	}
+
+	if result.Distance != 1147.42 {
+		t.Errorf("incorrect distance, got %v", result.Distance)
+	}
+
+	if result.Location == nil || !result.Location.IsPoint() || result.Location.Point[0] != -73.9927 {
+		t.Errorf("incorrect location, got %+v", result.Location)
+	}
+
+	if _, err := DecodeGeoNearResult[place](NewGeoNearStage(Point{1, 2}, "missing"), raw); err == nil {
+		t.Errorf("should fail when the distance field is missing")
+	}
+
+	legacy := map[string]interface{}{
+		"array":    bson.A{-73.9927, 40.7194},
+		"embedded": bson.D{{Key: "lng", Value: -73.9927}, {Key: "lat", Value: 40.7194}},
+		"x y":      bson.D{{Key: "x", Value: -73.9927}, {Key: "y", Value: 40.7194}},
+	}
+	for name, loc := range legacy {
+		raw, err := bson.Marshal(bson.D{
+			{Key: "name", Value: "Sara D. Roosevelt Park"},
+			{Key: "dist", Value: bson.D{
+				{Key: "calculated", Value: 0.01},
+				{Key: "location", Value: loc},
+			}},
+		})
+		if err != nil {
+			t.Fatalf("%s: should marshal document just fine but got %v", name, err)
+		}
+
+		result, err := DecodeGeoNearResult[place](stage, raw)
+		if err != nil {
+			t.Fatalf("%s: should decode legacy location just fine but got %v", name, err)
+		}
+
+		if result.Location == nil || !result.Location.IsPoint() || !result.Location.Point.Equal(Point{-73.9927, 40.7194}, 0) {
+			t.Errorf("%s: incorrect location, got %+v", name, result.Location)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+package geojson
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// A GeoIndexType is the type of a geospatial index.
+type GeoIndexType string
+
+// The geospatial index types, see EnsureGeoIndex.
+const (
+	// Index2DSphere indexes GeoJSON geometries on a sphere, for GeoWithin,
+	// GeoIntersects, Near and NearSphere.
+	Index2DSphere GeoIndexType = "2dsphere"
+
+	// Index2D indexes legacy coordinate pairs on a plane, see LegacyPoint.
+	Index2D GeoIndexType = "2d"
+)
+
+// SphereIndexVersion is the 2dsphereIndexVersion of the 2dsphere indexes
+// created by EnsureGeoIndex, the latest one, supported since MongoDB 3.2.
+const SphereIndexVersion = 3
+
+// ErrGeoIndexConflict is returned by EnsureGeoIndex when an index on the same
+// keys already exists with different options.
+var ErrGeoIndexConflict = errors.New("geo index already exists with different options")
+
+// GeoIndexOptions are the options of the index created by EnsureGeoIndex.
+type GeoIndexOptions struct {
+	// Type is the type of the index, Index2DSphere when empty.
+	Type GeoIndexType
+
+	// Name is the name of the index, MongoDB names it after its keys when empty,
+	// e.g. location_2dsphere.
+	Name string
+
+	// Keys are the other keys of a compound index, after the geospatial field,
+	// e.g. bson.D{{Key: "category", Value: 1}}.
+	Keys bson.D
+
+	// PartialFilter only indexes the documents matching the filter, e.g.
+	// bson.D{{Key: "deleted", Value: false}}, it is compared to the filter of an
+	// existing index regardless of the order of the fields.
+	PartialFilter interface{}
+
+	// Sparse only indexes the documents that have the indexed fields, 2dsphere
+	// indexes are always sparse on the geospatial field.
+	Sparse bool
+
+	// Bits is the precision of a 2d index, 26 when 0.
+	Bits int32
+
+	// Min and Max are the bounds of the coordinates of a 2d index, Min is -180
+	// when 0 and Max is 180 when 0.
+	Min float64
+	Max float64
+}
+
+// EnsureGeoIndex creates a geospatial index on field unless the collection
+// already has an index on the same keys with the same options, it returns the
+// name of the index. 2dsphere indexes are created with SphereIndexVersion.
+// It fails with ErrGeoIndexConflict when an index on the same keys has
+// different options, as creating it would fail.
+// https://docs.mongodb.com/v4.2/core/2dsphere/
+// https://docs.mongodb.com/v4.2/core/2d/
+func EnsureGeoIndex(ctx context.Context, coll *mongo.Collection, field string, opts GeoIndexOptions) (string, error) {
+	model, err := geoIndexModel(field, opts)
+	if err != nil {
+		return "", err
+	}
+
+	cursor, err := coll.Indexes().List(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	var specs []bson.Raw
+	if err = cursor.All(ctx, &specs); err != nil {
+		return "", err
+	}
+
+	keys, err := bson.Marshal(model.Keys)
+	if err != nil {
+		return "", err
+	}
+
+	for _, spec := range specs {
+		if key, ok := spec.Lookup("key").DocumentOK(); !ok || !equalIndexKeys(key, keys) {
+			continue
+		}
+
+		name, _ := spec.Lookup("name").StringValueOK()
+		if (opts.Name != "" && name != opts.Name) || !equalIndexOptions(spec, model.Options) {
+			return "", fmt.Errorf("%w: %s", ErrGeoIndexConflict, name)
+		}
+
+		return name, nil
+	}
+
+	return coll.Indexes().CreateOne(ctx, model)
+}
+
+// geoIndexModel returns the model of the index created by EnsureGeoIndex.
+func geoIndexModel(field string, opts GeoIndexOptions) (mongo.IndexModel, error) {
+	if field == "" {
+		return mongo.IndexModel{}, errors.New("geo index field must not be empty")
+	}
+
+	t := opts.Type
+	if t == "" {
+		t = Index2DSphere
+	}
+
+	o := options.Index()
+	switch t {
+	case Index2DSphere:
+		o.SetSphereVersion(SphereIndexVersion)
+	case Index2D:
+		if opts.Bits != 0 {
+			o.SetBits(opts.Bits)
+		}
+		if opts.Min != 0 || opts.Max != 0 {
+			lower, upper := opts.Min, opts.Max
+			if lower == 0 {
+				lower = -180
+			}
+			if upper == 0 {
+				upper = 180
+			}
+			o.SetMin(lower).SetMax(upper)
+		}
+	default:
+		return mongo.IndexModel{}, fmt.Errorf("unknown geo index type %q", t)
+	}
+
+	if opts.Name != "" {
+		o.SetName(opts.Name)
+	}
+	if opts.PartialFilter != nil {
+		o.SetPartialFilterExpression(opts.PartialFilter)
+	}
+	if opts.Sparse {
+		o.SetSparse(true)
+	}
+
+	keys := append(bson.D{{Key: field, Value: string(t)}}, opts.Keys...)
+	return mongo.IndexModel{Keys: keys, Options: o}, nil
+}
+
+// equalIndexKeys returns true if the key documents have the same fields in the
+// same order with the same values, 1 and 1.0 are the same.
+func equalIndexKeys(a, b bson.Raw) bool {
+	ae, err := a.Elements()
+	if err != nil {
+		return false
+	}
+	be, err := b.Elements()
+	if err != nil || len(ae) != len(be) {
+		return false
+	}
+
+	for i := range ae {
+		if ae[i].Key() != be[i].Key() || !equalIndexValues(ae[i].Value(), be[i].Value()) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// equalIndexOptions returns true if the index spec listed by the server has
+// the options EnsureGeoIndex would create it with.
+func equalIndexOptions(spec bson.Raw, o *options.IndexOptions) bool {
+	sparse, _ := spec.Lookup("sparse").BooleanOK()
+	if sparse != (o.Sparse != nil && *o.Sparse) {
+		return false
+	}
+
+	numbers := []struct {
+		key   string
+		value *float64
+	}{
+		{"2dsphereIndexVersion", int32Option(o.SphereVersion)},
+		{"bits", int32Option(o.Bits)},
+		{"min", o.Min},
+		{"max", o.Max},
+	}
+	for _, n := range numbers {
+		v := spec.Lookup(n.key)
+		if n.value == nil {
+			if v.Type != 0 {
+				return false
+			}
+			continue
+		}
+
+		if f, ok := rawValueNumber(v); !ok || f != *n.value {
+			return false
+		}
+	}
+
+	filter := spec.Lookup("partialFilterExpression")
+	if o.PartialFilterExpression == nil {
+		return filter.Type == 0
+	}
+
+	expected, err := bson.Marshal(o.PartialFilterExpression)
+	if err != nil {
+		return false
+	}
+
+	return equalFilterValues(filter, bson.RawValue{Type: bsontype.EmbeddedDocument, Value: expected})
+}
+
+// int32Option converts an integer option to a float64 to compare it.
+func int32Option(i *int32) *float64 {
+	if i == nil {
+		return nil
+	}
+
+	f := float64(*i)
+	return &f
+}
+
+// rawValueNumber returns the value of a double, an int32 or an int64.
+func rawValueNumber(v bson.RawValue) (float64, bool) {
+	return rawNumber(bsoncore.Value{Type: v.Type, Data: v.Value})
+}
+
+// equalIndexValues returns true if the values are the same string or number.
+func equalIndexValues(a, b bson.RawValue) bool {
+	if an, ok := rawValueNumber(a); ok {
+		bn, ok := rawValueNumber(b)
+		return ok && an == bn
+	}
+
+	return a.Equal(b)
+}
+
+// equalFilterValues returns true if the values of a filter are the same, the
+// fields of the documents may be in any order as a bson.M is marshaled in random
+// order, the elements of the arrays are in the same order, 1 and 1.0 are the same.
+func equalFilterValues(a, b bson.RawValue) bool {
+	switch {
+	case a.Type == bsontype.EmbeddedDocument && b.Type == bsontype.EmbeddedDocument:
+		ae, err := a.Document().Elements()
+		if err != nil {
+			return false
+		}
+		be, err := b.Document().Elements()
+		if err != nil || len(ae) != len(be) {
+			return false
+		}
+
+		for _, e := range ae {
+			v, err := b.Document().LookupErr(e.Key())
+			if err != nil || !equalFilterValues(e.Value(), v) {
+				return false
+			}
+		}
+
+		return true
+	case a.Type == bsontype.Array && b.Type == bsontype.Array:
+		av, err := a.Array().Values()
+		if err != nil {
+			return false
+		}
+		bv, err := b.Array().Values()
+		if err != nil || len(av) != len(bv) {
+			return false
+		}
+
+		for i := range av {
+			if !equalFilterValues(av[i], bv[i]) {
+				return false
+			}
+		}
+
+		return true
+	}
+
+	return equalIndexValues(a, b)
+}
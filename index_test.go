@@ -0,0 +1,343 @@
+package geojson
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/mongo"
+	"go.mongodb.org/mongo-driver/mongo/address"
+	"go.mongodb.org/mongo-driver/mongo/description"
+	"go.mongodb.org/mongo-driver/mongo/options"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+	"go.mongodb.org/mongo-driver/x/mongo/driver"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/drivertest"
+	"go.mongodb.org/mongo-driver/x/mongo/driver/wiremessage"
+)
+
+// mockDeployment is a single server deployment answering the commands with
+// the responses in order, it records the commands it receives.
+type mockDeployment struct {
+	responses []bson.D
+	commands  []bson.Raw
+	updates   chan description.Topology
+}
+
+var (
+	_ driver.Deployment   = &mockDeployment{}
+	_ driver.Server       = &mockDeployment{}
+	_ driver.Connection   = &mockDeployment{}
+	_ driver.Connector    = &mockDeployment{}
+	_ driver.Disconnector = &mockDeployment{}
+	_ driver.Subscriber   = &mockDeployment{}
+)
+
+func (md *mockDeployment) SelectServer(context.Context, description.ServerSelector) (driver.Server, error) {
+	return md, nil
+}
+
+func (md *mockDeployment) Kind() description.TopologyKind { return description.Single }
+
+func (md *mockDeployment) Connection(context.Context) (driver.Connection, error) { return md, nil }
+
+func (md *mockDeployment) MinRTT() time.Duration { return 0 }
+
+func (md *mockDeployment) RTT90() time.Duration { return 0 }
+
+func (md *mockDeployment) Connect() error { return nil }
+
+func (md *mockDeployment) Disconnect(context.Context) error { return nil }
+
+func (md *mockDeployment) Subscribe() (*driver.Subscription, error) {
+	if md.updates == nil {
+		md.updates = make(chan description.Topology, 1)
+		md.updates <- description.Topology{SessionTimeoutMinutes: 30}
+	}
+
+	return &driver.Subscription{Updates: md.updates}, nil
+}
+
+func (md *mockDeployment) Unsubscribe(*driver.Subscription) error { return nil }
+
+// WriteWireMessage records a copy of the command of an OP_MSG, the driver reuses
+// the buffer to read the response.
+func (md *mockDeployment) WriteWireMessage(_ context.Context, wm []byte) error {
+	command, err := drivertest.GetCommandFromMsgWireMessage(wm)
+	if err != nil {
+		return err
+	}
+
+	md.commands = append(md.commands, append(bson.Raw(nil), command...))
+	return nil
+}
+
+// ReadWireMessage returns the next response.
+func (md *mockDeployment) ReadWireMessage(_ context.Context, dst []byte) ([]byte, error) {
+	if len(md.responses) == 0 {
+		return dst, errors.New("no responses remaining")
+	}
+	response, _ := bson.Marshal(md.responses[0])
+	md.responses = md.responses[1:]
+
+	var index int32
+	index, dst = wiremessage.AppendHeaderStart(dst, wiremessage.NextRequestID(), 0, wiremessage.OpMsg)
+	dst = wiremessage.AppendMsgFlags(dst, 0)
+	dst = wiremessage.AppendMsgSectionType(dst, wiremessage.SingleDocument)
+	dst = append(dst, response...)
+	return bsoncore.UpdateLength(dst, index, int32(len(dst[index:]))), nil
+}
+
+func (md *mockDeployment) Description() description.Server {
+	return description.Server{
+		CanonicalAddr:   md.Address(),
+		MaxDocumentSize: 16777216,
+		MaxMessageSize:  48000000,
+		MaxBatchCount:   100000,
+		Kind:            description.Standalone,
+		WireVersion:     &description.VersionRange{Min: 6, Max: 13},
+	}
+}
+
+func (md *mockDeployment) Close() error { return nil }
+
+func (md *mockDeployment) ID() string { return "mock" }
+
+func (md *mockDeployment) ServerConnectionID() *int32 { return nil }
+
+func (md *mockDeployment) Address() address.Address { return "localhost:27017" }
+
+func (md *mockDeployment) Stale() bool { return false }
+
+func mockCollection(t *testing.T, responses ...bson.D) (*mongo.Collection, *mockDeployment) {
+	md := &mockDeployment{responses: responses}
+
+	opts := options.Client()
+	opts.Deployment = md
+	client, err := mongo.Connect(context.Background(), opts)
+	if err != nil {
+		t.Fatalf("should connect to the mock deployment just fine but got %v", err)
+	}
+
+	return client.Database("db").Collection("places"), md
+}
+
+func listIndexesResponse(specs ...bson.D) bson.D {
+	batch := bson.A{bson.D{{Key: "v", Value: 2}, {Key: "key", Value: bson.D{{Key: "_id", Value: 1}}}, {Key: "name", Value: "_id_"}}}
+	for _, spec := range specs {
+		batch = append(batch, spec)
+	}
+
+	return bson.D{
+		{Key: "ok", Value: 1},
+		{Key: "cursor", Value: bson.D{
+			{Key: "id", Value: int64(0)},
+			{Key: "ns", Value: "db.places"},
+			{Key: "firstBatch", Value: batch},
+		}},
+	}
+}
+
+var okResponse = bson.D{{Key: "ok", Value: 1}}
+
+func TestEnsureGeoIndexCreates(t *testing.T) {
+	coll, md := mockCollection(t, listIndexesResponse(), okResponse)
+
+	name, err := EnsureGeoIndex(context.Background(), coll, "location", GeoIndexOptions{
+		Keys:          bson.D{{Key: "category", Value: 1}},
+		PartialFilter: bson.D{{Key: "deleted", Value: false}},
+		Sparse:        true,
+	})
+	if err != nil {
+		t.Fatalf("should create the index just fine but got %v", err)
+	}
+
+	if name != "location_2dsphere_category_1" {
+		t.Errorf("incorrect name, got %v", name)
+	}
+
+	if len(md.commands) != 2 || md.commands[0].Lookup("listIndexes").StringValue() != "places" ||
+		md.commands[1].Lookup("createIndexes").StringValue() != "places" {
+		t.Fatalf("should list then create the indexes, got %v", md.commands)
+	}
+
+	indexes, _ := md.commands[1].Lookup("indexes").Array().Values()
+	if len(indexes) != 1 {
+		t.Fatalf("should create one index, got %v", md.commands[1])
+	}
+
+	index := indexes[0].Document()
+	expected := bson.D{{Key: "location", Value: "2dsphere"}, {Key: "category", Value: 1}}
+	if keys, _ := bson.Marshal(expected); !equalIndexKeys(index.Lookup("key").Document(), keys) {
+		t.Errorf("incorrect keys, got %v", index.Lookup("key"))
+	}
+
+	if v, _ := rawValueNumber(index.Lookup("2dsphereIndexVersion")); v != SphereIndexVersion {
+		t.Errorf("should set the 2dsphere index version, got %v", index)
+	}
+
+	if !index.Lookup("sparse").Boolean() || index.Lookup("partialFilterExpression", "deleted").Boolean() {
+		t.Errorf("incorrect options, got %v", index)
+	}
+}
+
+func TestEnsureGeoIndexExisting(t *testing.T) {
+	existing := bson.D{
+		{Key: "v", Value: 2},
+		{Key: "key", Value: bson.D{{Key: "location", Value: "2dsphere"}, {Key: "category", Value: 1.0}}},
+		{Key: "name", Value: "places_location"},
+		{Key: "2dsphereIndexVersion", Value: 3},
+	}
+
+	coll, md := mockCollection(t, listIndexesResponse(existing))
+
+	name, err := EnsureGeoIndex(context.Background(), coll, "location", GeoIndexOptions{
+		Keys: bson.D{{Key: "category", Value: 1}},
+	})
+	if err != nil {
+		t.Fatalf("should find the index just fine but got %v", err)
+	}
+
+	if name != "places_location" {
+		t.Errorf("should return the name of the existing index, got %v", name)
+	}
+
+	if len(md.commands) != 1 {
+		t.Errorf("should not create the index again, got %v", md.commands)
+	}
+
+	conflicts := []GeoIndexOptions{
+		{Keys: bson.D{{Key: "category", Value: 1}}, Sparse: true},
+		{Keys: bson.D{{Key: "category", Value: 1}}, Name: "location_category"},
+		{Keys: bson.D{{Key: "category", Value: 1}}, PartialFilter: bson.D{{Key: "deleted", Value: false}}},
+	}
+
+	for _, opts := range conflicts {
+		coll, md := mockCollection(t, listIndexesResponse(existing))
+
+		if _, err := EnsureGeoIndex(context.Background(), coll, "location", opts); !errors.Is(err, ErrGeoIndexConflict) {
+			t.Errorf("should fail with a conflict for %+v but got %v", opts, err)
+		}
+
+		if len(md.commands) != 1 {
+			t.Errorf("should not try to create the index, got %v", md.commands)
+		}
+	}
+}
+
+func TestEnsureGeoIndexPartialFilter(t *testing.T) {
+	existing := bson.D{
+		{Key: "v", Value: 2},
+		{Key: "key", Value: bson.D{{Key: "location", Value: "2dsphere"}}},
+		{Key: "name", Value: "location_2dsphere"},
+		{Key: "2dsphereIndexVersion", Value: 3},
+		{Key: "partialFilterExpression", Value: bson.D{
+			{Key: "status", Value: "open"},
+			{Key: "deleted", Value: false},
+			{Key: "rank", Value: bson.D{{Key: "$gt", Value: 5.0}}},
+			{Key: "tags", Value: bson.D{{Key: "$in", Value: bson.A{"a", "b"}}}},
+		}},
+	}
+
+	filter := bson.M{
+		"deleted": false,
+		"rank":    bson.M{"$gt": 5},
+		"status":  "open",
+		"tags":    bson.M{"$in": bson.A{"a", "b"}},
+	}
+
+	// a bson.M is marshaled in random order
+	for i := 0; i < 20; i++ {
+		coll, md := mockCollection(t, listIndexesResponse(existing))
+
+		name, err := EnsureGeoIndex(context.Background(), coll, "location", GeoIndexOptions{PartialFilter: filter})
+		if err != nil {
+			t.Fatalf("should find the index just fine but got %v", err)
+		}
+
+		if name != "location_2dsphere" || len(md.commands) != 1 {
+			t.Errorf("should return the existing index, got %v, %v", name, md.commands)
+		}
+	}
+
+	for _, f := range []bson.M{
+		{"deleted": false, "rank": bson.M{"$gt": 5}, "status": "open"},
+		{"deleted": false, "rank": bson.M{"$gt": 6}, "status": "open", "tags": bson.M{"$in": bson.A{"a", "b"}}},
+		{"deleted": false, "rank": bson.M{"$gt": 5}, "status": "open", "tags": bson.M{"$in": bson.A{"b", "a"}}},
+	} {
+		coll, _ := mockCollection(t, listIndexesResponse(existing))
+
+		if _, err := EnsureGeoIndex(context.Background(), coll, "location", GeoIndexOptions{PartialFilter: f}); !errors.Is(err, ErrGeoIndexConflict) {
+			t.Errorf("should fail with a conflict for %v but got %v", f, err)
+		}
+	}
+}
+
+func TestEnsureGeoIndex2D(t *testing.T) {
+	coll, md := mockCollection(t, listIndexesResponse(), okResponse)
+
+	name, err := EnsureGeoIndex(context.Background(), coll, "loc", GeoIndexOptions{
+		Type: Index2D,
+		Name: "loc_grid",
+		Bits: 32,
+		Min:  -1000,
+		Max:  1000,
+	})
+	if err != nil {
+		t.Fatalf("should create the index just fine but got %v", err)
+	}
+
+	if name != "loc_grid" {
+		t.Errorf("incorrect name, got %v", name)
+	}
+
+	index := md.commands[1].Lookup("indexes", "0").Document()
+	if index.Lookup("key", "loc").StringValue() != "2d" || index.Lookup("bits").Int32() != 32 ||
+		index.Lookup("min").Double() != -1000 || index.Lookup("max").Double() != 1000 {
+		t.Errorf("incorrect index, got %v", index)
+	}
+
+	if _, err := index.LookupErr("2dsphereIndexVersion"); err == nil {
+		t.Errorf("should not set the 2dsphere index version of a 2d index")
+	}
+
+	for _, c := range []struct {
+		opts     GeoIndexOptions
+		min, max float64
+	}{
+		{GeoIndexOptions{Type: Index2D, Min: -90}, -90, 180},
+		{GeoIndexOptions{Type: Index2D, Max: 90}, -180, 90},
+	} {
+		coll, md := mockCollection(t, listIndexesResponse(), okResponse)
+
+		if _, err := EnsureGeoIndex(context.Background(), coll, "loc", c.opts); err != nil {
+			t.Fatalf("should create the index just fine but got %v", err)
+		}
+
+		index := md.commands[1].Lookup("indexes", "0").Document()
+		if index.Lookup("min").Double() != c.min || index.Lookup("max").Double() != c.max {
+			t.Errorf("incorrect bounds for %+v, got %v", c.opts, index)
+		}
+	}
+}
+
+func TestEnsureGeoIndexInvalid(t *testing.T) {
+	for _, c := range []struct {
+		field string
+		opts  GeoIndexOptions
+	}{
+		{"", GeoIndexOptions{}},
+		{"loc", GeoIndexOptions{Type: "geoHaystack"}},
+	} {
+		coll, md := mockCollection(t)
+
+		if _, err := EnsureGeoIndex(context.Background(), coll, c.field, c.opts); err == nil {
+			t.Errorf("should fail for %q, %+v", c.field, c.opts)
+		}
+
+		if len(md.commands) != 0 {
+			t.Errorf("should not send any command, got %v", md.commands)
+		}
+	}
+}
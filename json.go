@@ -0,0 +1,207 @@
+package geojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// decodeJSONObject decodes a JSON object into the same shape bson.Unmarshal produces
+// for a map[string]interface{}, JSON arrays become primitive.A,
+// so the BSON decode helpers can be shared. A JSON null returns a nil map.
+// Integral numbers become int64 so that ids and properties are stored without
+// loss, the other numbers float64.
+func decodeJSONObject(data []byte) (map[string]interface{}, error) {
+	if bytes.Equal(bytes.TrimSpace(data), []byte("null")) {
+		return nil, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var object map[string]interface{}
+	if err := dec.Decode(&object); err != nil {
+		return nil, err
+	}
+	if dec.More() {
+		return nil, errors.New("invalid character after top-level value")
+	}
+	convertJSONArrays(object)
+
+	return object, nil
+}
+
+func convertJSONArrays(object map[string]interface{}) {
+	for k, v := range object {
+		object[k] = convertJSONValue(v)
+	}
+}
+
+func convertJSONValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case []interface{}:
+		a := make(primitive.A, len(v))
+		for i, e := range v {
+			a[i] = convertJSONValue(e)
+		}
+		return a
+	case map[string]interface{}:
+		convertJSONArrays(v)
+	case json.Number:
+		return convertJSONNumber(v)
+	}
+	return v
+}
+
+// convertJSONNumber returns an integral number as an int64 when it fits, as a float64 otherwise.
+func convertJSONNumber(n json.Number) interface{} {
+	if i, err := strconv.ParseInt(string(n), 10, 64); err == nil {
+		return i
+	}
+
+	f, _ := n.Float64()
+	return f
+}
+
+// checkJSONNumbers fails for the first bbox element or position of the geometry,
+// the members of a collection excluded, holding a NaN or an infinite number, e.g.
+// the M-only positions Point{x, y, NaN, m} of UnmarshalWKT, JSON has no such numbers.
+func checkJSONNumbers(g *Geometry) error {
+	if !finiteNumbers(g.BBox) {
+		return fmt.Errorf("not a valid GeoJSON bbox, got %v: JSON has no NaN or infinite numbers", g.BBox)
+	}
+
+	var lines [][]Point
+	switch g.Type {
+	case GeometryPoint:
+		lines = [][]Point{{g.Point}}
+	case GeometryMultiPoint:
+		lines = [][]Point{g.MultiPoint}
+	case GeometryLineString:
+		lines = [][]Point{g.LineString}
+	case GeometryMultiLineString:
+		lines = g.MultiLineString
+	case GeometryPolygon:
+		lines = g.Polygon
+	case GeometryMultiPolygon:
+		for _, polygon := range g.MultiPolygon {
+			lines = append(lines, polygon...)
+		}
+	}
+
+	for _, line := range lines {
+		for _, p := range line {
+			if !finiteNumbers(p) {
+				return fmt.Errorf("not a valid GeoJSON position, got %v: JSON has no NaN or infinite numbers", p)
+			}
+		}
+	}
+
+	return nil
+}
+
+func finiteNumbers(values []float64) bool {
+	for _, f := range values {
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// rawDocumentJSON converts a BSON document into plain JSON keeping the element
+// order, unlike Extended JSON the numbers are written as JSON numbers, ObjectIDs
+// as hexadecimal strings and dates as RFC 3339 strings. It fails for the values
+// JSON has no equivalent of, e.g. binary data or NaN.
+func rawDocumentJSON(doc bson.Raw) ([]byte, error) {
+	var b bytes.Buffer
+	if err := writeRawJSON(&b, bson.RawValue{Type: bsontype.EmbeddedDocument, Value: doc}); err != nil {
+		return nil, err
+	}
+
+	return b.Bytes(), nil
+}
+
+func writeRawJSON(b *bytes.Buffer, v bson.RawValue) error {
+	switch v.Type {
+	case bsontype.EmbeddedDocument:
+		elements, err := v.Document().Elements()
+		if err != nil {
+			return err
+		}
+
+		b.WriteByte('{')
+		for i, e := range elements {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			key, _ := json.Marshal(e.Key())
+			b.Write(key)
+			b.WriteByte(':')
+			if err := writeRawJSON(b, e.Value()); err != nil {
+				return err
+			}
+		}
+		b.WriteByte('}')
+	case bsontype.Array:
+		values, err := v.Array().Values()
+		if err != nil {
+			return err
+		}
+
+		b.WriteByte('[')
+		for i, value := range values {
+			if i > 0 {
+				b.WriteByte(',')
+			}
+			if err := writeRawJSON(b, value); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(']')
+	case bsontype.Double:
+		f := v.Double()
+		if !finiteNumbers([]float64{f}) {
+			return fmt.Errorf("not a valid JSON number, got %v", f)
+		}
+		b.WriteString(strconv.FormatFloat(f, 'g', -1, 64))
+	case bsontype.Int32:
+		b.WriteString(strconv.FormatInt(int64(v.Int32()), 10))
+	case bsontype.Int64:
+		b.WriteString(strconv.FormatInt(v.Int64(), 10))
+	case bsontype.Decimal128:
+		d := v.Decimal128()
+		if d.IsNaN() || d.IsInf() != 0 {
+			return fmt.Errorf("not a valid JSON number, got %v", d)
+		}
+		b.WriteString(d.String())
+	case bsontype.String, bsontype.Symbol:
+		s, ok := v.StringValueOK()
+		if !ok {
+			s = v.Symbol()
+		}
+		data, _ := json.Marshal(s)
+		b.Write(data)
+	case bsontype.Boolean:
+		b.WriteString(strconv.FormatBool(v.Boolean()))
+	case bsontype.Null, bsontype.Undefined:
+		b.WriteString("null")
+	case bsontype.ObjectID:
+		b.WriteString(strconv.Quote(v.ObjectID().Hex()))
+	case bsontype.DateTime:
+		b.WriteString(strconv.Quote(v.Time().UTC().Format(time.RFC3339Nano)))
+	default:
+		return fmt.Errorf("not a valid JSON value, got %s", v.Type)
+	}
+
+	return nil
+}
@@ -0,0 +1,142 @@
+package geojson
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// A LegacyPoint correlates to a MongoDB legacy coordinate pair, the location of
+// the documents indexed with a 2d index, stored either as an array [lng, lat]
+// or as an embedded document {lng: <lng>, lat: <lat>}.
+// https://docs.mongodb.com/v4.2/geospatial-queries/#legacy-coordinate-pairs
+type LegacyPoint struct {
+	Lng float64
+	Lat float64
+
+	// Embedded writes the pair as an embedded document rather than an array,
+	// it is set when decoding an embedded document so the pair is written back
+	// in the same form.
+	Embedded bool
+}
+
+// NewLegacyPoint creates a legacy coordinate pair stored as an array.
+func NewLegacyPoint(lng, lat float64) LegacyPoint {
+	return LegacyPoint{Lng: lng, Lat: lat}
+}
+
+// LegacyPointFromGeometry creates a legacy coordinate pair from a Point geometry,
+// the altitude is dropped.
+func LegacyPointFromGeometry(g *Geometry) (LegacyPoint, error) {
+	if g == nil || g.Type != GeometryPoint {
+		return LegacyPoint{}, fmt.Errorf("not a valid point geometry, got %v", g)
+	}
+
+	if len(g.Point) < 2 {
+		return LegacyPoint{}, ErrInvalidPosition
+	}
+
+	return NewLegacyPoint(g.Point[0], g.Point[1]), nil
+}
+
+// Point returns the position of the pair.
+func (p LegacyPoint) Point() Point {
+	return Point{p.Lng, p.Lat}
+}
+
+// Geometry returns the pair as a Point geometry, see NewPoint.
+func (p LegacyPoint) Geometry() *Geometry {
+	return NewPoint(p.Point())
+}
+
+// MarshalBSONValue writes the pair as an array, or as an embedded document
+// with the lng and lat fields when Embedded is set.
+// MarshalBSONValue implements bson.ValueMarshaler
+// nolint: gocritic
+func (p LegacyPoint) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if p.Embedded {
+		return bsontype.EmbeddedDocument, bsoncore.BuildDocumentFromElements(nil,
+			bsoncore.AppendDoubleElement(nil, "lng", p.Lng),
+			bsoncore.AppendDoubleElement(nil, "lat", p.Lat),
+		), nil
+	}
+
+	return bsontype.Array, bsoncore.BuildArray(nil,
+		bsoncore.Value{Type: bsontype.Double, Data: bsoncore.AppendDouble(nil, p.Lng)},
+		bsoncore.Value{Type: bsontype.Double, Data: bsoncore.AppendDouble(nil, p.Lat)},
+	), nil
+}
+
+// UnmarshalBSONValue reads a pair stored as an array or as an embedded document.
+// Like a 2d index, it takes the first two values, whatever the names of the
+// fields of the document, e.g. {x: <lng>, y: <lat>}. A null is the zero pair.
+// UnmarshalBSONValue implements bson.ValueUnmarshaler
+func (p *LegacyPoint) UnmarshalBSONValue(t bsontype.Type, data []byte) error {
+	if t == bsontype.Null {
+		*p = LegacyPoint{}
+		return nil
+	}
+
+	var (
+		values []bsoncore.Value
+		err    error
+	)
+
+	switch t {
+	case bsontype.Array:
+		values, err = bsoncore.Array(data).Values()
+	case bsontype.EmbeddedDocument:
+		var elements []bsoncore.Element
+		elements, err = bsoncore.Document(data).Elements()
+		for _, e := range elements {
+			values = append(values, e.Value())
+		}
+	default:
+		return &DecodeError{Expected: "legacy coordinate pair", Actual: t}
+	}
+	if err != nil {
+		return &DecodeError{Err: err}
+	}
+
+	if len(values) < 2 {
+		return &DecodeError{Err: fmt.Errorf("legacy coordinate pair must have 2 values, got %d", len(values))}
+	}
+
+	var pair [2]float64
+	for i, v := range values[:2] {
+		f, ok := rawNumber(v)
+		if !ok {
+			return decodeErrorAtIndex(&DecodeError{Expected: "coordinate", Actual: v.Type}, i)
+		}
+		pair[i] = f
+	}
+
+	*p = LegacyPoint{Lng: pair[0], Lat: pair[1], Embedded: t == bsontype.EmbeddedDocument}
+	return nil
+}
+
+// UnmarshalLegacyPoint decodes a BSON value, e.g. the result of
+// cursor.Current.Lookup("loc"), into a legacy coordinate pair.
+func UnmarshalLegacyPoint(v bson.RawValue) (LegacyPoint, error) {
+	var p LegacyPoint
+	err := p.UnmarshalBSONValue(v.Type, v.Value)
+	return p, err
+}
+
+// rawNumber returns the value of a double, an int32 or an int64.
+func rawNumber(v bsoncore.Value) (float64, bool) {
+	switch v.Type {
+	case bsontype.Double:
+		return v.DoubleOK()
+	case bsontype.Int32:
+		i, ok := v.Int32OK()
+		return float64(i), ok
+	case bsontype.Int64:
+		i, ok := v.Int64OK()
+		return float64(i), ok
+	}
+
+	return 0, false
+}
@@ -0,0 +1,138 @@
+package geojson
+
+import (
+	"errors"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+type legacyDocument struct {
+	Loc  LegacyPoint  `bson:"loc"`
+	Near *LegacyPoint `bson:"near,omitempty"`
+}
+
+func TestLegacyPointMarshal(t *testing.T) {
+	cases := []struct {
+		name string
+		doc  legacyDocument
+		want string
+	}{
+		{
+			"array",
+			legacyDocument{Loc: NewLegacyPoint(-73.97, 40.77)},
+			`{"loc":[-73.97,40.77]}`,
+		},
+		{
+			"embedded document",
+			legacyDocument{Loc: LegacyPoint{Lng: -73.97, Lat: 40.77, Embedded: true}},
+			`{"loc":{"lng":-73.97,"lat":40.77}}`,
+		},
+		{
+			"pointer",
+			legacyDocument{Loc: NewLegacyPoint(1, 2), Near: &LegacyPoint{Lng: 3, Lat: 4}},
+			`{"loc":[1.0,2.0],"near":[3.0,4.0]}`,
+		},
+	}
+
+	for _, c := range cases {
+		data, err := bson.Marshal(c.doc)
+		if err != nil {
+			t.Fatalf("%s: should marshal just fine but got %v", c.name, err)
+		}
+
+		if blob, _ := bson.MarshalExtJSON(bson.Raw(data), false, false); string(blob) != c.want {
+			t.Errorf("%s: incorrect document\n got %s\nwant %s", c.name, blob, c.want)
+		}
+
+		var doc legacyDocument
+		if err = bson.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s: should unmarshal just fine but got %v", c.name, err)
+		}
+
+		if doc.Loc != c.doc.Loc || (doc.Near == nil) != (c.doc.Near == nil) {
+			t.Errorf("%s: should decode back the same pairs, got %+v", c.name, doc)
+		}
+	}
+}
+
+func TestLegacyPointUnmarshal(t *testing.T) {
+	cases := []struct {
+		name string
+		loc  interface{}
+		want LegacyPoint
+	}{
+		{"integers", bson.A{int32(1), int64(2)}, LegacyPoint{Lng: 1, Lat: 2}},
+		{"extra values", bson.A{1.5, 2.5, 3.5}, LegacyPoint{Lng: 1.5, Lat: 2.5}},
+		{"any field names", bson.D{{Key: "x", Value: 3}, {Key: "y", Value: 4.5}}, LegacyPoint{Lng: 3, Lat: 4.5, Embedded: true}},
+		{"null", nil, LegacyPoint{}},
+	}
+
+	for _, c := range cases {
+		data, _ := bson.Marshal(bson.M{"loc": c.loc})
+
+		p, err := UnmarshalLegacyPoint(bson.Raw(data).Lookup("loc"))
+		if err != nil {
+			t.Fatalf("%s: should unmarshal just fine but got %v", c.name, err)
+		}
+
+		if p != c.want {
+			t.Errorf("%s: incorrect pair, got %+v", c.name, p)
+		}
+
+		doc := legacyDocument{Loc: NewLegacyPoint(9, 9)}
+		if err = bson.Unmarshal(data, &doc); err != nil {
+			t.Fatalf("%s: should unmarshal the document just fine but got %v", c.name, err)
+		}
+
+		if doc.Loc != c.want {
+			t.Errorf("%s: incorrect document, got %+v", c.name, doc)
+		}
+	}
+
+	invalid := []interface{}{
+		"-73.97,40.77",
+		bson.A{1},
+		bson.A{1, "2"},
+		bson.D{{Key: "lng", Value: 1}},
+	}
+
+	for _, loc := range invalid {
+		data, _ := bson.Marshal(bson.M{"loc": loc})
+
+		var doc legacyDocument
+		var e *DecodeError
+		if err := bson.Unmarshal(data, &doc); !errors.As(err, &e) {
+			t.Errorf("should fail to unmarshal %v with a DecodeError but got %v", loc, err)
+		}
+	}
+
+	data, _ := bson.Marshal(bson.M{"loc": bson.A{1, true}})
+	_, err := UnmarshalLegacyPoint(bson.Raw(data).Lookup("loc"))
+	var e *DecodeError
+	if !errors.As(err, &e) || e.Path != "[1]" || e.Actual != bsontype.Boolean {
+		t.Errorf("should report the invalid coordinate, got %v", err)
+	}
+}
+
+func TestLegacyPointGeometry(t *testing.T) {
+	g := NewLegacyPoint(-73.97, 40.77).Geometry()
+	if g.Type != GeometryPoint || !g.Point.Equal(Point{-73.97, 40.77}, 0) {
+		t.Errorf("incorrect geometry, got %+v", g)
+	}
+
+	p, err := LegacyPointFromGeometry(NewPoint(Point{1, 2, 3}))
+	if err != nil {
+		t.Fatalf("should convert just fine but got %v", err)
+	}
+	if p != NewLegacyPoint(1, 2) {
+		t.Errorf("incorrect pair, got %+v", p)
+	}
+
+	for _, g := range []*Geometry{nil, NewLineString([]Point{{1, 2}, {3, 4}}), NewPoint(Point{1})} {
+		if _, err := LegacyPointFromGeometry(g); err == nil {
+			t.Errorf("should fail to convert %v", g)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+package geojson
+
+import (
+	"math"
+)
+
+// EarthRadius is the radius of the earth in meters used by MongoDB for spherical
+// distances, e.g. $geoNear with spherical: true and $centerSphere.
+const EarthRadius = 6378100.0
+
+// Measure selects the earth model used to compute areas and lengths.
+type Measure int
+
+const (
+	// Geodesic measures on the WGS84 ellipsoid with Karney's algorithm,
+	// accurate to a few nanometers for lengths.
+	Geodesic Measure = iota
+
+	// Spherical measures with great circles on a sphere of EarthRadius,
+	// it is faster and within about 0.5% of Geodesic.
+	Spherical
+)
+
+// Area returns the area of the geometry in square meters on the WGS84 ellipsoid.
+// Holes are subtracted from their polygon, collections sum the area of their
+// members, points and lines have no area.
+// Like a MongoDB 2dsphere index, a ring encloses the smaller of the two regions
+// it separates, whatever its winding order, except the exterior ring of a polygon
+// with the strict winding CRS that encloses the region on its left, see NewBigPolygon.
+func (g *Geometry) Area() float64 {
+	return Geodesic.Area(g)
+}
+
+// Length returns the length of the lines of the geometry in meters on the WGS84 ellipsoid.
+// Collections sum the length of their members, points and polygons have no length,
+// see Perimeter for the latter.
+func (g *Geometry) Length() float64 {
+	return Geodesic.Length(g)
+}
+
+// Perimeter returns the length of the rings of the polygons of the geometry in meters
+// on the WGS84 ellipsoid, holes included.
+// Collections sum the perimeter of their members, points and lines have no perimeter.
+func (g *Geometry) Perimeter() float64 {
+	return Geodesic.Perimeter(g)
+}
+
+// Area returns the area of the geometry in square meters, see Geometry.Area.
+func (m Measure) Area(g *Geometry) float64 {
+	if g == nil {
+		return 0
+	}
+
+	switch g.Type {
+	case GeometryPolygon:
+		return m.polygonArea(g.Polygon, g.CRS.IsStrictWinding())
+	case GeometryMultiPolygon:
+		area := 0.0
+		for _, polygon := range g.MultiPolygon {
+			area += m.polygonArea(polygon, g.CRS.IsStrictWinding())
+		}
+		return area
+	case GeometryCollection:
+		area := 0.0
+		for _, geometry := range g.Geometries {
+			area += m.Area(geometry)
+		}
+		return area
+	}
+
+	return 0
+}
+
+// Length returns the length of the lines of the geometry in meters, see Geometry.Length.
+func (m Measure) Length(g *Geometry) float64 {
+	if g == nil {
+		return 0
+	}
+
+	switch g.Type {
+	case GeometryLineString:
+		return m.lineLength(g.LineString)
+	case GeometryMultiLineString:
+		length := 0.0
+		for _, line := range g.MultiLineString {
+			length += m.lineLength(line)
+		}
+		return length
+	case GeometryCollection:
+		length := 0.0
+		for _, geometry := range g.Geometries {
+			length += m.Length(geometry)
+		}
+		return length
+	}
+
+	return 0
+}
+
+// Perimeter returns the length of the rings of the geometry in meters, see Geometry.Perimeter.
+func (m Measure) Perimeter(g *Geometry) float64 {
+	if g == nil {
+		return 0
+	}
+
+	switch g.Type {
+	case GeometryPolygon:
+		return m.polygonPerimeter(g.Polygon)
+	case GeometryMultiPolygon:
+		perimeter := 0.0
+		for _, polygon := range g.MultiPolygon {
+			perimeter += m.polygonPerimeter(polygon)
+		}
+		return perimeter
+	case GeometryCollection:
+		perimeter := 0.0
+		for _, geometry := range g.Geometries {
+			perimeter += m.Perimeter(geometry)
+		}
+		return perimeter
+	}
+
+	return 0
+}
+
+// polygonArea returns the area of the polygon, with strict the exterior ring
+// encloses the region on its left.
+func (m Measure) polygonArea(polygon [][]Point, strict bool) float64 {
+	if len(polygon) == 0 {
+		return 0
+	}
+
+	area := m.ringArea(polygon[0])
+	if !strict {
+		area = math.Abs(area)
+	} else if area < 0 {
+		area += m.earthArea()
+	}
+	for _, hole := range polygon[1:] {
+		area -= math.Abs(m.ringArea(hole))
+	}
+
+	return math.Max(area, 0)
+}
+
+func (m Measure) polygonPerimeter(polygon [][]Point) float64 {
+	perimeter := 0.0
+	for _, ring := range polygon {
+		perimeter += m.lineLength(ring)
+	}
+
+	return perimeter
+}
+
+// ringArea returns the counter-clockwise positive area of the ring, the ring
+// does not have to be closed.
+func (m Measure) ringArea(ring []Point) float64 {
+	ring = openRing(ring)
+	if len(ring) < 3 {
+		return 0
+	}
+
+	var area accumulator
+	crossings := 0
+	for i := range ring {
+		p, q := ring[i], ring[(i+1)%len(ring)]
+		if len(p) < 2 || len(q) < 2 {
+			continue
+		}
+
+		area.add(m.edgeArea(p, q))
+		crossings += transit(p[0], q[0])
+	}
+
+	return areaReduce(area, m.earthArea(), crossings)
+}
+
+// earthArea returns the area of the whole earth in square meters.
+func (m Measure) earthArea() float64 {
+	if m == Spherical {
+		return 4 * math.Pi * EarthRadius * EarthRadius
+	}
+	return 4 * math.Pi * wgs84.c2
+}
+
+// edgeArea returns the area between the edge from p to q and the equator.
+func (m Measure) edgeArea(p, q Point) float64 {
+	if m == Spherical {
+		// the spherical excess of the quadrilateral formed with the equator
+		// tan(E/2) = tan(dlon/2) * (tan(lat1/2)+tan(lat2/2))/(1+tan(lat1/2)*tan(lat2/2))
+		dlon := angDiff(p[0], q[0], nil) * degree
+		t1 := math.Tan(p[1] * degree / 2)
+		t2 := math.Tan(q[1] * degree / 2)
+		excess := 2 * math.Atan2(math.Tan(dlon/2)*(t1+t2), 1+t1*t2)
+		return excess * EarthRadius * EarthRadius
+	}
+
+	_, _, _, S12 := wgs84.inverse(p[1], p[0], q[1], q[0], true)
+	return S12
+}
+
+func (m Measure) lineLength(line []Point) float64 {
+	length := 0.0
+	for i := 1; i < len(line); i++ {
+		p, q := line[i-1], line[i]
+		if len(p) < 2 || len(q) < 2 {
+			continue
+		}
+
+		if m == Spherical {
+			length += haversine(p, q)
+		} else {
+			s12, _, _, _ := wgs84.inverse(p[1], p[0], q[1], q[0], false)
+			length += s12
+		}
+	}
+
+	return length
+}
+
+// haversine returns the great circle distance between p and q in meters.
+func haversine(p, q Point) float64 {
+	lat1, lat2 := p[1]*degree, q[1]*degree
+	dlat := lat2 - lat1
+	dlon := angDiff(p[0], q[0], nil) * degree
+
+	h := math.Sin(dlat/2)*math.Sin(dlat/2) + math.Cos(lat1)*math.Cos(lat2)*math.Sin(dlon/2)*math.Sin(dlon/2)
+	return 2 * EarthRadius * math.Asin(math.Sqrt(math.Min(h, 1)))
+}
+
+// openRing drops the closing position of a ring.
+func openRing(ring []Point) []Point {
+	if len(ring) > 1 && pointEqual(ring[0], ring[len(ring)-1]) {
+		return ring[:len(ring)-1]
+	}
+
+	return ring
+}
@@ -0,0 +1,105 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+)
+
+// the reference values are from GeographicLib's GeodSolve and Planimeter.
+func antarctica() []Point {
+	latlon := [][2]float64{
+		{-63.1, -58}, {-72.9, -74}, {-71.9, -102}, {-74.9, -102}, {-74.3, -131},
+		{-77.5, -163}, {-77.4, 163}, {-71.7, 172}, {-65.9, 140}, {-65.7, 113},
+		{-66.6, 88}, {-66.9, 59}, {-69.8, 25}, {-70.0, -4}, {-71.0, -14},
+		{-77.3, -33}, {-77.9, -46}, {-74.7, -61},
+	}
+
+	ring := make([]Point, 0, len(latlon)+1)
+	for _, p := range latlon {
+		ring = append(ring, Point{p[1], p[0]})
+	}
+
+	return append(ring, ring[0])
+}
+
+func TestGeometryLength(t *testing.T) {
+	// JFK to LHR
+	line := NewLineString([]Point{{-73.8, 40.6}, {-0.5, 51.6}})
+	if l := line.Length(); math.Abs(l-5551759.400) > 1e-3 {
+		t.Errorf("incorrect length, got %v", l)
+	}
+
+	if l := Spherical.Length(line); math.Abs(l-5551759.400)/5551759.400 > 0.005 {
+		t.Errorf("incorrect spherical length, got %v", l)
+	}
+
+	multi := NewMultiLineString([]Point{{-73.8, 40.6}, {-0.5, 51.6}}, []Point{{0, 0}, {0, 90}})
+	if l := multi.Length(); math.Abs(l-5551759.400-10001965.729) > 1e-3 {
+		t.Errorf("incorrect multilinestring length, got %v", l)
+	}
+
+	collection := NewGeometryCollection(NewPoint(Point{1, 2}), line, NewPolygon([][]Point{antarctica()}))
+	if l := collection.Length(); l != line.Length() {
+		t.Errorf("incorrect collection length, got %v", l)
+	}
+
+	// equatorial and antipodal lines
+	if l := NewLineString([]Point{{0, 0}, {180, 0}}).Length(); math.Abs(l-20003931.459) > 1e-3 {
+		t.Errorf("incorrect antipodal length, got %v", l)
+	}
+
+	if l := NewLineString([]Point{{0, 0}, {179.5, 0.5}}).Length(); math.Abs(l-19936288.579) > 1e-3 {
+		t.Errorf("incorrect nearly antipodal length, got %v", l)
+	}
+}
+
+func TestGeometryArea(t *testing.T) {
+	polygon := NewPolygon([][]Point{antarctica()})
+	if a := polygon.Area(); math.Abs(a-13662703680020.1) > 1 {
+		t.Errorf("incorrect area, got %v", a)
+	}
+
+	if p := polygon.Perimeter(); math.Abs(p-16831067.893) > 1e-3 {
+		t.Errorf("incorrect perimeter, got %v", p)
+	}
+
+	if a := Spherical.Area(polygon); math.Abs(a-13662703680020.1)/13662703680020.1 > 0.01 {
+		t.Errorf("incorrect spherical area, got %v", a)
+	}
+
+	// the winding order does not matter
+	reversed := make([]Point, 0, len(antarctica()))
+	for i := len(antarctica()) - 1; i >= 0; i-- {
+		reversed = append(reversed, antarctica()[i])
+	}
+	if a := NewPolygon([][]Point{reversed}).Area(); math.Abs(a-13662703680020.1) > 1 {
+		t.Errorf("incorrect area of a clockwise ring, got %v", a)
+	}
+
+	square := [][]Point{{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}}
+	if a := NewPolygon(square).Area(); math.Abs(a-12308778361.469) > 1e-2 {
+		t.Errorf("incorrect square area, got %v", a)
+	}
+
+	withHole := NewPolygon([][]Point{square[0], {{0.25, 0.25}, {0.75, 0.25}, {0.75, 0.75}, {0.25, 0.75}, {0.25, 0.25}}})
+	hole := NewPolygon([][]Point{withHole.Polygon[1]})
+	if a := withHole.Area(); math.Abs(a-(NewPolygon(square).Area()-hole.Area())) > 1e-3 {
+		t.Errorf("holes should be subtracted, got %v", a)
+	}
+
+	if p := withHole.Perimeter(); math.Abs(p-(NewPolygon(square).Perimeter()+hole.Perimeter())) > 1e-6 {
+		t.Errorf("holes should be part of the perimeter, got %v", p)
+	}
+
+	multi := NewMultiPolygon(square, [][]Point{antarctica()})
+	collection := NewGeometryCollection(multi, NewLineString([]Point{{0, 0}, {1, 1}}), NewPoint(Point{1, 2}))
+	if a := collection.Area(); math.Abs(a-12308778361.469-13662703680020.1) > 1 {
+		t.Errorf("incorrect collection area, got %v", a)
+	}
+
+	for _, g := range []*Geometry{NewPoint(Point{1, 2}), NewLineString([]Point{{0, 0}, {1, 1}}), nil} {
+		if a := g.Area(); a != 0 {
+			t.Errorf("%v should have no area, got %v", g, a)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package geojson
+
+// Orientation returns the winding order of the closed ring in the lon/lat plane:
+// 1 when counter-clockwise, -1 when clockwise and 0 when the ring has no area.
+// Longitude steps of more than 180 degrees are taken across the antimeridian,
+// so a ring crossing it is oriented as drawn on the map.
+func Orientation(ring []Point) int {
+	return sign(planarRingArea(ring))
+}
+
+// IsCCW returns true if the closed ring is counter-clockwise, see Orientation.
+func IsCCW(ring []Point) bool {
+	return Orientation(ring) > 0
+}
+
+// Rewind reverses the rings of the polygons of the geometry in place, those of
+// nested geometries included, so they follow RFC 7946: exterior rings are
+// counter-clockwise and holes are clockwise.
+// https://tools.ietf.org/html/rfc7946#section-3.1.6
+// With rfc7946 false the opposite winding is applied, clockwise exterior rings
+// and counter-clockwise holes, as expected by some older tools.
+// Rings without area are left as is, and so are the polygons with the strict
+// winding CRS since their winding order selects their interior, see NewBigPolygon.
+func (g *Geometry) Rewind(rfc7946 bool) {
+	if g == nil || g.CRS.IsStrictWinding() {
+		return
+	}
+
+	switch g.Type {
+	case GeometryPolygon:
+		rewindPolygon(g.Polygon, rfc7946)
+	case GeometryMultiPolygon:
+		for _, polygon := range g.MultiPolygon {
+			rewindPolygon(polygon, rfc7946)
+		}
+	case GeometryCollection:
+		for _, geometry := range g.Geometries {
+			geometry.Rewind(rfc7946)
+		}
+	}
+}
+
+func rewindPolygon(polygon [][]Point, rfc7946 bool) {
+	for i, ring := range polygon {
+		// the exterior ring is counter-clockwise and the holes clockwise with rfc7946
+		want := 1
+		if (i == 0) != rfc7946 {
+			want = -1
+		}
+
+		if o := Orientation(ring); o != 0 && o != want {
+			reverseRing(ring)
+		}
+	}
+}
+
+func reverseRing(ring []Point) {
+	for i, j := 0, len(ring)-1; i < j; i, j = i+1, j-1 {
+		ring[i], ring[j] = ring[j], ring[i]
+	}
+}
+
+// planarRingArea returns twice the signed area of the ring in the lon/lat plane,
+// positive when counter-clockwise, with the shoelace formula.
+func planarRingArea(ring []Point) float64 {
+	if len(ring) < 3 {
+		return 0
+	}
+
+	area, lon := 0.0, 0.0
+	for i := 0; i+1 < len(ring); i++ {
+		a, b := ring[i], ring[i+1]
+		if len(a) < 2 || len(b) < 2 {
+			return 0
+		}
+
+		// unwrap the longitudes relative to the first position
+		step := b[0] - a[0]
+		if step > 180 {
+			step -= 360
+		} else if step < -180 {
+			step += 360
+		}
+		area += lon*b[1] - (lon+step)*a[1]
+		lon += step
+	}
+
+	return area
+}
@@ -0,0 +1,65 @@
+package geojson
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestOrientation(t *testing.T) {
+	cases := []struct {
+		name string
+		ring []Point
+		want int
+	}{
+		{"counter-clockwise", []Point{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}}, 1},
+		{"clockwise", []Point{{0, 0}, {0, 1}, {1, 1}, {1, 0}, {0, 0}}, -1},
+		{"antimeridian", []Point{{179, 0}, {-179, 0}, {-179, 1}, {179, 1}, {179, 0}}, 1},
+		{"collinear", []Point{{0, 0}, {1, 1}, {2, 2}, {0, 0}}, 0},
+		{"too short", []Point{{0, 0}, {1, 1}}, 0},
+	}
+
+	for _, c := range cases {
+		if o := Orientation(c.ring); o != c.want {
+			t.Errorf("incorrect orientation for %s, got %d", c.name, o)
+		}
+
+		if IsCCW(c.ring) != (c.want == 1) {
+			t.Errorf("incorrect IsCCW for %s", c.name)
+		}
+	}
+}
+
+func TestRewind(t *testing.T) {
+	polygon := func() [][]Point {
+		return [][]Point{
+			{{0, 0}, {0, 10}, {10, 10}, {10, 0}, {0, 0}},
+			{{2, 2}, {4, 2}, {4, 4}, {2, 4}, {2, 2}},
+		}
+	}
+
+	g := NewGeometryCollection(NewPolygon(polygon()), NewMultiPolygon(polygon(), polygon()))
+	g.Rewind(true)
+
+	for _, p := range [][][]Point{g.Geometries[0].Polygon, g.Geometries[1].MultiPolygon[0], g.Geometries[1].MultiPolygon[1]} {
+		if !IsCCW(p[0]) || IsCCW(p[1]) {
+			t.Errorf("incorrect RFC 7946 winding, got %v", p)
+		}
+
+		if !reflect.DeepEqual(p[0][0], Point{0, 0}) || !pointEqual(p[0][0], p[0][4]) {
+			t.Errorf("ring should stay closed, got %v", p[0])
+		}
+	}
+
+	g.Rewind(false)
+	if p := g.Geometries[0].Polygon; IsCCW(p[0]) || !IsCCW(p[1]) {
+		t.Errorf("incorrect opposite winding, got %v", p)
+	}
+
+	if !reflect.DeepEqual(g.Geometries[0].Polygon, polygon()) {
+		t.Errorf("should rewind back to the original rings, got %v", g.Geometries[0].Polygon)
+	}
+
+	// nothing to rewind
+	(*Geometry)(nil).Rewind(true)
+	NewPoint(Point{1, 2}).Rewind(true)
+}
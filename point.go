@@ -0,0 +1,72 @@
+package geojson
+
+import (
+	"math"
+)
+
+// NewPointLonLat creates a position from its longitude and latitude, in that order.
+func NewPointLonLat(lon, lat float64) Point {
+	return Point{lon, lat}
+}
+
+// NewPointLonLatAlt creates a position from its longitude, latitude and altitude.
+func NewPointLonLatAlt(lon, lat, alt float64) Point {
+	return Point{lon, lat, alt}
+}
+
+// Lon returns the longitude of the position, 0 if it has none, see Valid.
+func (p Point) Lon() float64 {
+	if len(p) < 1 {
+		return 0
+	}
+	return p[0]
+}
+
+// Lat returns the latitude of the position, 0 if it has none, see Valid.
+func (p Point) Lat() float64 {
+	if len(p) < 2 {
+		return 0
+	}
+	return p[1]
+}
+
+// Alt returns the altitude of the position, ok is false if it has none.
+func (p Point) Alt() (alt float64, ok bool) {
+	if len(p) < 3 {
+		return 0, false
+	}
+	return p[2], true
+}
+
+// Valid returns true if the position has two or three elements, a longitude in
+// [-180, 180], a latitude in [-90, 90] and a finite altitude.
+func (p Point) Valid() bool {
+	if !validPositionLength(p) || validatePosition(p, "") != nil {
+		return false
+	}
+
+	alt, ok := p.Alt()
+	return !ok || (!math.IsNaN(alt) && !math.IsInf(alt, 0))
+}
+
+// Equal returns true if the positions have the same number of elements and
+// their elements differ by at most epsilon, use 0 for an exact comparison.
+func (p Point) Equal(q Point, epsilon float64) bool {
+	if len(p) != len(q) {
+		return false
+	}
+
+	for i := range p {
+		if p[i] != q[i] && !(math.Abs(p[i]-q[i]) <= epsilon) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// validPositionLength returns true if the position has a longitude, a latitude
+// and at most an altitude.
+func validPositionLength(p Point) bool {
+	return len(p) == 2 || len(p) == 3
+}
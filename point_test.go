@@ -0,0 +1,106 @@
+package geojson
+
+import (
+	"math"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestPointAccessors(t *testing.T) {
+	p := NewPointLonLat(2.35, 48.85)
+	if p.Lon() != 2.35 || p.Lat() != 48.85 {
+		t.Errorf("incorrect position, got %v", p)
+	}
+
+	if _, ok := p.Alt(); ok {
+		t.Errorf("should not have an altitude")
+	}
+
+	if alt, ok := NewPointLonLatAlt(2.35, 48.85, 35).Alt(); !ok || alt != 35 {
+		t.Errorf("incorrect altitude, got %v, %v", alt, ok)
+	}
+
+	if empty := (Point{}); empty.Lon() != 0 || empty.Lat() != 0 {
+		t.Errorf("should be 0 for a position without coordinates")
+	}
+}
+
+func TestPointValid(t *testing.T) {
+	cases := []struct {
+		p     Point
+		valid bool
+	}{
+		{Point{2.35, 48.85}, true},
+		{Point{-180, -90, -10}, true},
+		{Point{180, 90}, true},
+		{Point{48.85}, false},
+		{Point{}, false},
+		{Point{1, 2, 3, 4}, false},
+		{Point{181, 0}, false},
+		{Point{0, -91}, false},
+		{Point{math.NaN(), 0}, false},
+		{Point{0, math.Inf(1)}, false},
+		{Point{0, 0, math.NaN()}, false},
+	}
+
+	for _, c := range cases {
+		if c.p.Valid() != c.valid {
+			t.Errorf("incorrect validity of %v, got %v", c.p, !c.valid)
+		}
+	}
+}
+
+func TestPointEqual(t *testing.T) {
+	p := Point{1, 2, 3}
+	if !p.Equal(Point{1, 2, 3}, 0) {
+		t.Errorf("should be equal")
+	}
+
+	if p.Equal(Point{1, 2}, 1) {
+		t.Errorf("should not be equal to a position without altitude")
+	}
+
+	if !p.Equal(Point{1 + 1e-10, 2, 3 - 1e-10}, 1e-9) || p.Equal(Point{1 + 1e-8, 2, 3}, 1e-9) {
+		t.Errorf("incorrect comparison with epsilon")
+	}
+
+	if inf := (Point{math.Inf(1), 0}); !inf.Equal(Point{math.Inf(1), 0}, 0) {
+		t.Errorf("should be equal to itself")
+	}
+}
+
+func TestDecodeStrictPositions(t *testing.T) {
+	opts := DecodeOptions{StrictPositions: true}
+	registry := RegisterGeometryCodec(bson.NewRegistryBuilder()).
+		RegisterTypeDecoder(tGeometryPtr, NewGeometryCodec(opts)).
+		Build()
+
+	cases := []struct {
+		geometry bson.D
+		valid    bool
+	}{
+		{bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1, 2}}}, true},
+		{bson.D{{Key: "type", Value: "Point"}, {Key: "bbox", Value: bson.A{1, 2, 0, 1, 2, 0}}, {Key: "coordinates", Value: bson.A{1, 2, 3}}}, true},
+		{bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1}}}, false},
+		{bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{1, 2, 3, 4}}}, false},
+		{bson.D{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{bson.A{1, 2}, bson.A{}}}}, false},
+		{bson.D{{Key: "type", Value: "MultiPolygon"}, {Key: "coordinates", Value: bson.A{bson.A{bson.A{bson.A{1, 2}, bson.A{3}}}}}}, false},
+	}
+
+	for _, c := range cases {
+		blob, _ := bson.Marshal(c.geometry)
+		if _, err := UnmarshalGeometry(blob); err != nil {
+			t.Errorf("should decode %v without the option but got %v", c.geometry, err)
+		}
+
+		if _, err := UnmarshalGeometryWithOptions(blob, opts); (err == nil) != c.valid {
+			t.Errorf("incorrect strict decoding of %v, got %v", c.geometry, err)
+		}
+
+		data, _ := bson.Marshal(bson.M{"geometry": c.geometry})
+		if err := bson.UnmarshalWithRegistry(registry, data, &codecDocument{}); (err == nil) != c.valid {
+			t.Errorf("incorrect strict codec decoding of %v, got %v", c.geometry, err)
+		}
+	}
+}
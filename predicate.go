@@ -0,0 +1,167 @@
+package geojson
+
+// The spatial predicates follow the OGC simple features definitions, evaluated
+// with the semantics of a MongoDB 2dsphere index so an in-memory answer agrees
+// with the server:
+// edges are the shortest great circle arcs between two positions, not straight
+// lines in the longitude/latitude plane, a ring encloses the smaller of the two
+// regions it separates whatever its winding order, unless it is the exterior ring
+// of a big polygon, see NewBigPolygon, and the antimeridian is not a boundary.
+// https://docs.mongodb.com/v4.2/reference/operator/query/geoIntersects/
+// https://docs.mongodb.com/v4.2/reference/operator/query/geoWithin/
+// The predicates compare every edge of one geometry with every edge of the other,
+// they are meant for the shapes of everyday queries, not for very large polygons.
+
+// Intersects returns true if the geometries share at least one point, boundaries
+// included. It matches $geoIntersects with g as the query geometry.
+func (g *Geometry) Intersects(other *Geometry) bool {
+	return intersects(newSphereShape(g), newSphereShape(other))
+}
+
+// Disjoint returns true if the geometries do not share any point,
+// it is the negation of Intersects.
+func (g *Geometry) Disjoint(other *Geometry) bool {
+	return !g.Intersects(other)
+}
+
+// Covers returns true if no point of the other geometry lies outside g,
+// points on the boundary of g are covered.
+// It is the closest match of $geoWithin with g as the query geometry, apart from
+// the positions lying exactly on the boundary of g that the server may not match.
+func (g *Geometry) Covers(other *Geometry) bool {
+	return covers(newSphereShape(g), newSphereShape(other))
+}
+
+// Contains returns true if no point of the other geometry lies outside g and at
+// least one point of the interior of the other geometry lies in the interior of g.
+// Unlike Covers, a polygon does not contain a line lying along its boundary.
+func (g *Geometry) Contains(other *Geometry) bool {
+	a, b := newSphereShape(g), newSphereShape(other)
+	return covers(a, b) && interiorsIntersect(a, b)
+}
+
+// Within returns true if g is contained by the other geometry, see Contains.
+func (g *Geometry) Within(other *Geometry) bool {
+	return other.Contains(g)
+}
+
+// Touches returns true if the geometries share at least one point but their
+// interiors do not intersect, e.g. two polygons with a common edge.
+func (g *Geometry) Touches(other *Geometry) bool {
+	a, b := newSphereShape(g), newSphereShape(other)
+	return intersects(a, b) && !interiorsIntersect(a, b)
+}
+
+func intersects(a, b *sphereShape) bool {
+	for _, ea := range [][][2]vector{a.lineEdges, a.ringEdges} {
+		for _, eb := range [][][2]vector{b.lineEdges, b.ringEdges} {
+			for _, x := range ea {
+				for _, y := range eb {
+					if arcsIntersect(x[0], x[1], y[0], y[1]) {
+						return true
+					}
+				}
+			}
+		}
+	}
+
+	// no edges meet, one of the shapes may still lie inside the other
+	for _, v := range a.vertices {
+		if b.locate(v) != locExterior {
+			return true
+		}
+	}
+
+	for _, v := range b.vertices {
+		if a.locate(v) != locExterior {
+			return true
+		}
+	}
+
+	return false
+}
+
+func covers(a, b *sphereShape) bool {
+	if a.isEmpty() || b.isEmpty() {
+		return false
+	}
+
+	outside := func(p vector) bool {
+		return a.locate(p) == locExterior
+	}
+
+	for _, v := range b.vertices {
+		if outside(v) {
+			return false
+		}
+	}
+
+	for _, edges := range [][][2]vector{b.lineEdges, b.ringEdges} {
+		for _, e := range edges {
+			if edgeSamples(e[0], e[1], a, false, outside) {
+				return false
+			}
+		}
+	}
+
+	if len(b.polygons) == 0 {
+		return true
+	}
+
+	// only polygons cover an area
+	if len(a.polygons) == 0 {
+		return false
+	}
+
+	// the inner side of every edge of b must be covered,
+	// this rejects a polygon b lying in a hole of a.
+	for _, e := range b.ringEdges {
+		if edgeSamples(e[0], e[1], a, true, func(p vector) bool {
+			return b.locate(p) == locInterior && outside(p)
+		}) {
+			return false
+		}
+	}
+
+	// a ring of a going through the interior of b, e.g. a hole, leaves part of b uncovered
+	for _, e := range a.ringEdges {
+		if edgeSamples(e[0], e[1], b, false, func(p vector) bool {
+			return b.locate(p) == locInterior
+		}) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func interiorsIntersect(a, b *sphereShape) bool {
+	inBoth := func(p vector) bool {
+		return a.locate(p) == locInterior && b.locate(p) == locInterior
+	}
+
+	for _, vertices := range [][]vector{a.vertices, b.vertices} {
+		for _, v := range vertices {
+			if inBoth(v) {
+				return true
+			}
+		}
+	}
+
+	for _, pair := range [][2]*sphereShape{{a, b}, {b, a}} {
+		s, other := pair[0], pair[1]
+		for _, e := range s.lineEdges {
+			if edgeSamples(e[0], e[1], other, false, inBoth) {
+				return true
+			}
+		}
+
+		for _, e := range s.ringEdges {
+			if edgeSamples(e[0], e[1], other, true, inBoth) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,160 @@
+package geojson
+
+import (
+	"testing"
+)
+
+func square(west, south, east, north float64) [][]Point {
+	return [][]Point{{{west, south}, {east, south}, {east, north}, {west, north}, {west, south}}}
+}
+
+func TestGeometryPredicatesPointInPolygon(t *testing.T) {
+	zone := NewPolygon(square(-100, 40, -80, 50))
+
+	cases := []struct {
+		point  Point
+		inside bool
+	}{
+		{Point{-90, 45}, true},
+		{Point{-110, 45}, false},
+		// the southern edge is a great circle arc bulging north to 40.43
+		{Point{-90, 40.3}, false},
+		{Point{-90, 40.5}, true},
+		// so is the northern one, up to 50.43
+		{Point{-90, 50.3}, true},
+		{Point{-90, 50.5}, false},
+	}
+
+	for _, c := range cases {
+		fix := NewPoint(c.point)
+		if got := zone.Contains(fix); got != c.inside {
+			t.Errorf("zone contains %v should be %v", c.point, c.inside)
+		}
+
+		if got := fix.Within(zone); got != c.inside {
+			t.Errorf("%v within zone should be %v", c.point, c.inside)
+		}
+
+		if got := zone.Intersects(fix); got != c.inside {
+			t.Errorf("zone intersects %v should be %v", c.point, c.inside)
+		}
+
+		if got := fix.Disjoint(zone); got == c.inside {
+			t.Errorf("%v disjoint zone should be %v", c.point, !c.inside)
+		}
+	}
+
+	// the winding order does not matter, the smaller region is the interior
+	clockwise := NewPolygon([][]Point{{{-100, 40}, {-100, 50}, {-80, 50}, {-80, 40}, {-100, 40}}})
+	if !clockwise.Contains(NewPoint(Point{-90, 45})) || clockwise.Contains(NewPoint(Point{90, -45})) {
+		t.Errorf("a clockwise ring should enclose the smaller region")
+	}
+
+	// a vertex is on the boundary
+	vertex := NewPoint(Point{-100, 40})
+	if zone.Contains(vertex) || !zone.Covers(vertex) || !zone.Touches(vertex) || !zone.Intersects(vertex) {
+		t.Errorf("a vertex should be covered but not contained")
+	}
+
+	// across the antimeridian
+	pacific := NewPolygon([][]Point{{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}}})
+	for _, p := range []Point{{180, 0}, {-180, 0}, {175, 5}, {-175, -5}} {
+		if !pacific.Contains(NewPoint(p)) {
+			t.Errorf("pacific should contain %v", p)
+		}
+	}
+	if pacific.Contains(NewPoint(Point{0, 0})) {
+		t.Errorf("pacific should not contain the null island")
+	}
+
+	// a hole
+	donut := NewPolygon([][]Point{square(0, 0, 10, 10)[0], square(4, 4, 6, 6)[0]})
+	if donut.Contains(NewPoint(Point{5, 5})) || !donut.Contains(NewPoint(Point{2, 2})) {
+		t.Errorf("a hole should not be part of the polygon")
+	}
+
+	// around a pole
+	arctic := NewPolygon([][]Point{{{0, 80}, {90, 80}, {180, 80}, {-90, 80}, {0, 80}}})
+	if !arctic.Contains(NewPoint(Point{45, 89})) || arctic.Contains(NewPoint(Point{45, 70})) {
+		t.Errorf("arctic should contain the north pole area")
+	}
+}
+
+func TestGeometryPredicates(t *testing.T) {
+	poly := NewPolygon(square(0, 0, 10, 10))
+	inner := NewPolygon(square(2, 2, 4, 4))
+	overlapping := NewPolygon(square(5, 5, 15, 15))
+	adjacent := NewPolygon(square(10, 0, 20, 10))
+	far := NewPolygon(square(50, 50, 60, 60))
+	donut := NewPolygon([][]Point{square(0, 0, 10, 10)[0], square(1, 1, 5, 5)[0]})
+
+	crossing := NewLineString([]Point{{-5, 5}, {15, 5}})
+	insideLine := NewLineString([]Point{{1, 1}, {3, 3}})
+	edgeLine := NewLineString([]Point{{0, 2}, {0, 4}})
+	otherLine := NewLineString([]Point{{5, -5}, {5, 15}})
+	endToEnd := NewLineString([]Point{{15, 5}, {20, 5}})
+
+	cases := []struct {
+		name                                          string
+		a, b                                          *Geometry
+		intersects, covers, contains, touches, within bool
+	}{
+		{"polygon/inner polygon", poly, inner, true, true, true, false, false},
+		{"polygon/itself", poly, poly, true, true, true, false, true},
+		{"polygon/overlapping", poly, overlapping, true, false, false, false, false},
+		{"polygon/adjacent", poly, adjacent, true, false, false, true, false},
+		{"polygon/far", poly, far, false, false, false, false, false},
+		{"donut/polygon in the hole", donut, inner, false, false, false, false, false},
+		{"polygon/crossing line", poly, crossing, true, false, false, false, false},
+		{"polygon/inner line", poly, insideLine, true, true, true, false, false},
+		{"polygon/boundary line", poly, edgeLine, true, true, false, true, false},
+		{"line/crossing line", crossing, otherLine, true, false, false, false, false},
+		{"line/line end to end", crossing, endToEnd, true, false, false, true, false},
+		// a meridian is a great circle, unlike a parallel
+		{"line/point on line", otherLine, NewPoint(Point{5, 0}), true, true, true, false, false},
+		{"line/point near parallel", crossing, NewPoint(Point{0, 5}), false, false, false, false, false},
+		{"line/end point", otherLine, NewPoint(Point{5, -5}), true, true, false, true, false},
+		{"multipoint/point", NewMultiPoint(Point{1, 1}, Point{2, 2}), NewPoint(Point{2, 2}), true, true, true, false, false},
+		{"point/point", NewPoint(Point{2, 2}), NewPoint(Point{2, 3}), false, false, false, false, false},
+		{
+			"multipolygon/collection",
+			NewMultiPolygon(square(0, 0, 10, 10), square(20, 20, 30, 30)),
+			NewGeometryCollection(NewPoint(Point{25, 25}), insideLine, inner),
+			true, true, true, false, false,
+		},
+		{
+			"multilinestring/polygon",
+			NewMultiLineString([]Point{{-5, -5}, {-1, -1}}, []Point{{5, 5}, {6, 6}}),
+			poly,
+			true, false, false, false, false,
+		},
+		{"polygon/nil", poly, nil, false, false, false, false, false},
+	}
+
+	for _, c := range cases {
+		if got := c.a.Intersects(c.b); got != c.intersects {
+			t.Errorf("%s: intersects should be %v", c.name, c.intersects)
+		}
+		if got := c.a.Disjoint(c.b); got == c.intersects {
+			t.Errorf("%s: disjoint should be %v", c.name, !c.intersects)
+		}
+		if got := c.a.Covers(c.b); got != c.covers {
+			t.Errorf("%s: covers should be %v", c.name, c.covers)
+		}
+		if got := c.a.Contains(c.b); got != c.contains {
+			t.Errorf("%s: contains should be %v", c.name, c.contains)
+		}
+		if got := c.b.Within(c.a); got != c.contains {
+			t.Errorf("%s: reversed within should be %v", c.name, c.contains)
+		}
+		if got := c.a.Touches(c.b); got != c.touches {
+			t.Errorf("%s: touches should be %v", c.name, c.touches)
+		}
+		if got := c.a.Within(c.b); got != c.within {
+			t.Errorf("%s: within should be %v", c.name, c.within)
+		}
+		if got := c.b.Covers(c.a) && c.b.Contains(c.a); got != c.within {
+			t.Errorf("%s: reversed contains should be %v", c.name, c.within)
+		}
+	}
+}
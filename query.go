@@ -0,0 +1,105 @@
+package geojson
+
+import (
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+// GeoWithin builds a $geoWithin filter selecting documents whose field lies
+// entirely within the given Polygon or MultiPolygon geometry.
+// The crs member of the geometry is kept in $geometry, so a polygon created with
+// NewBigPolygon selects the documents within a region larger than a hemisphere.
+// https://docs.mongodb.com/v4.2/reference/operator/query/geoWithin/
+//
+//	{ <field>: { $geoWithin: { $geometry: <geometry> } } }
+func GeoWithin(field string, g *Geometry) bson.D {
+	return geoOperator(field, "$geoWithin", bson.D{{Key: "$geometry", Value: g}})
+}
+
+// GeoIntersects builds a $geoIntersects filter selecting documents whose field
+// intersects with the given geometry, which may be a big polygon, see GeoWithin.
+// https://docs.mongodb.com/v4.2/reference/operator/query/geoIntersects/
+//
+//	{ <field>: { $geoIntersects: { $geometry: <geometry> } } }
+func GeoIntersects(field string, g *Geometry) bson.D {
+	return geoOperator(field, "$geoIntersects", bson.D{{Key: "$geometry", Value: g}})
+}
+
+// Near builds a $near filter returning documents sorted from nearest to farthest
+// from the given point, it requires a 2dsphere index on field.
+// min and max are distances in meters, a value <= 0 leaves the bound out.
+// https://docs.mongodb.com/v4.2/reference/operator/query/near/
+//
+//	{ <field>: { $near: { $geometry: <point>, $minDistance: <min>, $maxDistance: <max> } } }
+func Near(field string, p Point, min, max float64) bson.D {
+	return geoOperator(field, "$near", nearSpec(p, min, max))
+}
+
+// NearSphere builds a $nearSphere filter, it is the same as Near but always
+// calculates distances using spherical geometry.
+// min and max are distances in meters, a value <= 0 leaves the bound out.
+// https://docs.mongodb.com/v4.2/reference/operator/query/nearSphere/
+//
+//	{ <field>: { $nearSphere: { $geometry: <point>, $minDistance: <min>, $maxDistance: <max> } } }
+func NearSphere(field string, p Point, min, max float64) bson.D {
+	return geoOperator(field, "$nearSphere", nearSpec(p, min, max))
+}
+
+// GeoWithinCenterSphere builds a $geoWithin filter with the legacy $centerSphere shape,
+// selecting documents within a spherical cap. The radius is in radians,
+// divide a distance in meters by the radius of the earth to convert it.
+// https://docs.mongodb.com/v4.2/reference/operator/query/centerSphere/
+//
+//	{ <field>: { $geoWithin: { $centerSphere: [ [ <x>, <y> ], <radius> ] } } }
+func GeoWithinCenterSphere(field string, center Point, radius float64) bson.D {
+	return geoOperator(field, "$geoWithin", bson.D{{Key: "$centerSphere", Value: bson.A{center, radius}}})
+}
+
+// GeoWithinBox builds a $geoWithin filter with the legacy $box shape,
+// selecting documents within the rectangle given by its bottom left and upper right corners.
+// https://docs.mongodb.com/v4.2/reference/operator/query/box/
+//
+//	{ <field>: { $geoWithin: { $box: [ [ <bottom left> ], [ <upper right> ] ] } } }
+func GeoWithinBox(field string, bottomLeft, upperRight Point) bson.D {
+	return geoOperator(field, "$geoWithin", bson.D{{Key: "$box", Value: bson.A{bottomLeft, upperRight}}})
+}
+
+// GeoWithinPolygon builds a $geoWithin filter with the legacy $polygon shape,
+// selecting documents within the polygon given by its vertices, the polygon is
+// closed implicitly. It is planar and requires a 2d index on field.
+// https://docs.mongodb.com/v4.2/reference/operator/query/polygon/
+//
+//	{ <field>: { $geoWithin: { $polygon: [ [ <x1>, <y1> ], [ <x2>, <y2> ], [ <x3>, <y3> ], ... ] } } }
+func GeoWithinPolygon(field string, vertices ...Point) bson.D {
+	polygon := make(bson.A, 0, len(vertices))
+	for _, v := range vertices {
+		polygon = append(polygon, v)
+	}
+
+	return geoOperator(field, "$geoWithin", bson.D{{Key: "$polygon", Value: polygon}})
+}
+
+// GeoWithinCenter builds a $geoWithin filter with the legacy $center shape,
+// selecting documents within a circle on a flat surface. The radius is in the
+// units of the coordinates, it requires a 2d index on field.
+// https://docs.mongodb.com/v4.2/reference/operator/query/center/
+//
+//	{ <field>: { $geoWithin: { $center: [ [ <x>, <y> ], <radius> ] } } }
+func GeoWithinCenter(field string, center Point, radius float64) bson.D {
+	return geoOperator(field, "$geoWithin", bson.D{{Key: "$center", Value: bson.A{center, radius}}})
+}
+
+func geoOperator(field, operator string, spec bson.D) bson.D {
+	return bson.D{{Key: field, Value: bson.D{{Key: operator, Value: spec}}}}
+}
+
+func nearSpec(p Point, min, max float64) bson.D {
+	spec := bson.D{{Key: "$geometry", Value: NewPoint(p)}}
+	if min > 0 {
+		spec = append(spec, bson.E{Key: "$minDistance", Value: min})
+	}
+	if max > 0 {
+		spec = append(spec, bson.E{Key: "$maxDistance", Value: max})
+	}
+
+	return spec
+}
@@ -0,0 +1,75 @@
+package geojson
+
+import (
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestQueryBuilders(t *testing.T) {
+	polygon := NewPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}})
+
+	cases := []struct {
+		name   string
+		filter bson.D
+		want   string
+	}{
+		{
+			"geoWithin",
+			GeoWithin("loc", polygon),
+			`{"loc":{"$geoWithin":{"$geometry":{"type":"Polygon","coordinates":[[[0.0,0.0],[3.0,6.0],[6.0,1.0],[0.0,0.0]]]}}}}`,
+		},
+		{
+			"big polygon",
+			GeoWithin("loc", NewBigPolygon([]Point{{0, 0}, {6, 1}, {3, 6}, {0, 0}})),
+			`{"loc":{"$geoWithin":{"$geometry":{"type":"Polygon","coordinates":[[[0.0,0.0],[6.0,1.0],[3.0,6.0],[0.0,0.0]]],` +
+				`"crs":{"type":"name","properties":{"name":"urn:x-mongodb:crs:strictwinding:EPSG:4326"}}}}}}`,
+		},
+		{
+			"geoIntersects",
+			GeoIntersects("loc", NewPoint(Point{1, 2})),
+			`{"loc":{"$geoIntersects":{"$geometry":{"type":"Point","coordinates":[1.0,2.0]}}}}`,
+		},
+		{
+			"near",
+			Near("loc", Point{1, 2}, 10, 1000),
+			`{"loc":{"$near":{"$geometry":{"type":"Point","coordinates":[1.0,2.0]},"$minDistance":10.0,"$maxDistance":1000.0}}}`,
+		},
+		{
+			"nearSphere without min",
+			NearSphere("loc", Point{1, 2}, 0, 1000),
+			`{"loc":{"$nearSphere":{"$geometry":{"type":"Point","coordinates":[1.0,2.0]},"$maxDistance":1000.0}}}`,
+		},
+		{
+			"centerSphere",
+			GeoWithinCenterSphere("loc", Point{1, 2}, 0.5),
+			`{"loc":{"$geoWithin":{"$centerSphere":[[1.0,2.0],0.5]}}}`,
+		},
+		{
+			"box",
+			GeoWithinBox("loc", Point{0, 0}, Point{10, 10}),
+			`{"loc":{"$geoWithin":{"$box":[[0.0,0.0],[10.0,10.0]]}}}`,
+		},
+		{
+			"polygon",
+			GeoWithinPolygon("loc", Point{0, 0}, Point{3, 6}, Point{6, 0}),
+			`{"loc":{"$geoWithin":{"$polygon":[[0.0,0.0],[3.0,6.0],[6.0,0.0]]}}}`,
+		},
+		{
+			"center",
+			GeoWithinCenter("loc", Point{-74, 40.74}, 10),
+			`{"loc":{"$geoWithin":{"$center":[[-74.0,40.74],10.0]}}}`,
+		},
+	}
+
+	for _, c := range cases {
+		blob, err := bson.MarshalExtJSON(c.filter, false, false)
+		if err != nil {
+			t.Fatalf("%s: should marshal filter just fine but got %v", c.name, err)
+		}
+
+		if string(blob) != c.want {
+			t.Errorf("%s: incorrect filter\n got %s\nwant %s", c.name, blob, c.want)
+		}
+	}
+}
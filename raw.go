@@ -0,0 +1,302 @@
+package geojson
+
+import (
+	"fmt"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// DecodeGeometryRaw decodes a geometry from a BSON value, e.g. the result of
+// cursor.Current.Lookup("location"), reading the coordinates straight from the
+// bytes like GeometryCodec does.
+func DecodeGeometryRaw(v bson.RawValue) (*Geometry, error) {
+	if v.Type != bsontype.EmbeddedDocument {
+		return nil, &DecodeError{Expected: "geometry", Actual: v.Type}
+	}
+
+	g := &Geometry{}
+	err := newGeometryReader(DecodeOptions{}).readGeometryBytes(v.Value, g)
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+// RawGeometry is a read-only view of a BSON geometry document, it reads the type
+// and the coordinates from the bytes when asked, without decoding a Geometry.
+// It is meant for scanning many documents, e.g. counting positions or checking
+// bounds, a RawGeometry must not outlive the bytes it was created from.
+// GeometryCollections nested more than DefaultMaxDepth levels deep are rejected.
+type RawGeometry []byte
+
+// NewRawGeometry returns a view of the geometry document held by the BSON value.
+func NewRawGeometry(v bson.RawValue) (RawGeometry, error) {
+	if v.Type != bsontype.EmbeddedDocument {
+		return nil, &DecodeError{Expected: "geometry", Actual: v.Type}
+	}
+
+	if err := bsoncore.Document(v.Value).Validate(); err != nil {
+		return nil, err
+	}
+
+	return RawGeometry(v.Value), nil
+}
+
+// Type returns the type of the geometry.
+func (r RawGeometry) Type() (GeometryType, error) {
+	v, err := bsoncore.Document(r).LookupErr("type")
+	if err != nil {
+		return "", &DecodeError{Path: "type", Expected: "geometry type", Actual: bsontype.Null}
+	}
+
+	s, ok := v.StringValueOK()
+	if !ok {
+		return "", &DecodeError{Path: "type", Expected: "geometry type", Actual: v.Type}
+	}
+
+	return GeometryType(s), nil
+}
+
+// Decode decodes the whole geometry.
+func (r RawGeometry) Decode() (*Geometry, error) {
+	return DecodeGeometryRaw(bson.RawValue{Type: bsontype.EmbeddedDocument, Value: r})
+}
+
+// Geometries calls fn with every member of a GeometryCollection until fn returns false.
+func (r RawGeometry) Geometries(fn func(RawGeometry) bool) error {
+	v, err := bsoncore.Document(r).LookupErr("geometries")
+	if err != nil {
+		return &DecodeError{Path: "geometries", Expected: "set of geometries", Actual: bsontype.Null}
+	}
+
+	it, err := newRawArrayIterator(v, "set of geometries")
+	if err != nil {
+		return decodeErrorAt(err, "geometries")
+	}
+
+	for i := 0; ; i++ {
+		member, ok, err := it.next()
+		if err != nil || !ok {
+			return decodeErrorAt(err, "geometries")
+		}
+
+		doc, ok := member.DocumentOK()
+		if !ok {
+			err = decodeErrorAtIndex(&DecodeError{Expected: "geometry", Actual: member.Type}, i)
+			return decodeErrorAt(err, "geometries")
+		}
+
+		if !fn(RawGeometry(doc)) {
+			return nil
+		}
+	}
+}
+
+// Positions calls fn with every position of the geometry, those of the members
+// of a GeometryCollection included, in document order until fn returns false.
+// The position is only valid during the call, it must be copied to be kept.
+func (r RawGeometry) Positions(fn func(p Point) bool) error {
+	w := rawWalker{positions: fn}
+	_, err := w.walk(r)
+	return err
+}
+
+// NumPositions returns the number of positions of the geometry.
+func (r RawGeometry) NumPositions() (int, error) {
+	w := rawWalker{}
+	_, err := w.walk(r)
+	return w.count, err
+}
+
+// Bound returns the bound of the geometry, see Geometry.Bound.
+func (r RawGeometry) Bound() (Bound, error) {
+	w := rawWalker{bound: &boundExtender{}}
+	if _, err := w.walk(r); err != nil {
+		return Bound{}, err
+	}
+
+	return w.bound.bound(), nil
+}
+
+// rawWalker visits the positions of a RawGeometry, counting them, extending
+// a bound or calling a function.
+type rawWalker struct {
+	buf       [4]float64
+	points    bool
+	count     int
+	depth     int
+	bound     *boundExtender
+	positions func(Point) bool
+}
+
+// walk visits every position of the geometry, it returns false if the visit was stopped.
+func (w *rawWalker) walk(r RawGeometry) (bool, error) {
+	t, err := r.Type()
+	if err != nil {
+		return false, err
+	}
+
+	if t == GeometryCollection {
+		if w.depth++; w.depth > DefaultMaxDepth {
+			return false, &DecodeError{Path: "geometries", Err: fmt.Errorf("%w, more than %d levels", ErrMaxDepth, DefaultMaxDepth)}
+		}
+		defer func() { w.depth-- }()
+
+		more, i := true, 0
+		var memberErr error
+		err = r.Geometries(func(member RawGeometry) bool {
+			more, memberErr = w.walk(member)
+			if memberErr != nil {
+				memberErr = decodeErrorAt(decodeErrorAtIndex(memberErr, i), "geometries")
+			}
+			i++
+			return more && memberErr == nil
+		})
+		if err == nil {
+			err = memberErr
+		}
+		return more, err
+	}
+
+	if !t.IsKnown() {
+		return false, &DecodeError{Path: "type", Err: fmt.Errorf("%w %q", ErrUnknownType, t)}
+	}
+
+	v, err := bsoncore.Document(r).LookupErr("coordinates")
+	if err != nil {
+		return false, &DecodeError{Path: "coordinates", Expected: "coordinates", Actual: bsontype.Null}
+	}
+
+	w.points = t == GeometryPoint || t == GeometryMultiPoint
+	more, err := w.walkPositions(v, positionNesting(t))
+	return more, decodeErrorAt(err, "coordinates")
+}
+
+// positionNesting returns how many arrays enclose the positions of the coordinates
+// of a geometry of type t, 0 for a Point whose coordinates are the position.
+func positionNesting(t GeometryType) int {
+	switch t {
+	case GeometryMultiPoint, GeometryLineString:
+		return 1
+	case GeometryMultiLineString, GeometryPolygon:
+		return 2
+	case GeometryMultiPolygon:
+		return 3
+	}
+	return 0
+}
+
+// rawSetNames describes the arrays of positions by nesting, as decodeGeometry does.
+var rawSetNames = [...]string{"position", "set of positions", "set of paths", "set of polygons"}
+
+// walkPositions visits the positions found in v, nested in as many arrays as
+// nesting, a value nested differently is reported as a DecodeError.
+func (w *rawWalker) walkPositions(v bsoncore.Value, nesting int) (bool, error) {
+	if nesting == 0 {
+		return w.visit(v, true)
+	}
+
+	it, err := newRawArrayIterator(v, rawSetNames[nesting])
+	if err != nil {
+		return false, err
+	}
+
+	for i := 0; ; i++ {
+		c, ok, err := it.next()
+		if err != nil || !ok {
+			return true, err
+		}
+
+		var more bool
+		if nesting == 1 {
+			more, err = w.visit(c, i == 0)
+		} else {
+			more, err = w.walkPositions(c, nesting-1)
+		}
+		if err != nil {
+			return false, decodeErrorAtIndex(err, i)
+		}
+		if !more {
+			return false, nil
+		}
+	}
+}
+
+// visit reads the position held by v, first is true for the first position of a line.
+func (w *rawWalker) visit(v bsoncore.Value, first bool) (bool, error) {
+	it, err := newRawArrayIterator(v, "position")
+	if err != nil {
+		return false, err
+	}
+
+	p := Point(w.buf[:0])
+	for i := 0; ; i++ {
+		c, ok, err := it.next()
+		if err != nil {
+			return false, err
+		}
+		if !ok {
+			break
+		}
+
+		switch c.Type {
+		case bsontype.Double:
+			p = append(p, c.Double())
+		case bsontype.Int32:
+			p = append(p, float64(c.Int32()))
+		case bsontype.Int64:
+			p = append(p, float64(c.Int64()))
+		default:
+			return false, decodeErrorAtIndex(&DecodeError{Expected: "coordinate", Actual: c.Type}, i)
+		}
+	}
+
+	w.count++
+	if w.bound != nil {
+		w.bound.linePosition(p, first || w.points)
+	}
+	if w.positions != nil {
+		return w.positions(p), nil
+	}
+
+	return true, nil
+}
+
+// rawArrayIterator iterates over the elements of a BSON array.
+type rawArrayIterator struct {
+	rem  []byte
+	name string
+}
+
+// newRawArrayIterator fails if v is not an array, name describes the array in the errors.
+func newRawArrayIterator(v bsoncore.Value, name string) (rawArrayIterator, error) {
+	arr, ok := v.ArrayOK()
+	if !ok || len(arr) < 5 {
+		return rawArrayIterator{}, &DecodeError{Expected: name, Actual: v.Type}
+	}
+
+	return rawArrayIterator{rem: arr[4 : len(arr)-1], name: name}, nil
+}
+
+// next returns the next element, ok is false at the end of the array.
+func (it *rawArrayIterator) next() (value bsoncore.Value, ok bool, err error) {
+	if len(it.rem) == 0 {
+		return bsoncore.Value{}, false, nil
+	}
+
+	elem, rem, ok := bsoncore.ReadElement(it.rem)
+	if !ok {
+		return bsoncore.Value{}, false, &DecodeError{Err: fmt.Errorf("not a valid %s, corrupted array", it.name)}
+	}
+	it.rem = rem
+
+	value, err = elem.ValueErr()
+	if err != nil {
+		return bsoncore.Value{}, false, err
+	}
+
+	return value, true, nil
+}
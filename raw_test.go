@@ -0,0 +1,155 @@
+package geojson
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func rawDocument(t testing.TB, g interface{}) bson.Raw {
+	data, err := bson.Marshal(bson.M{"_id": 1, "location": g})
+	if err != nil {
+		t.Fatalf("should marshal just fine but got %v", err)
+	}
+
+	return bson.Raw(data)
+}
+
+func TestDecodeGeometryRaw(t *testing.T) {
+	for _, g := range codecGeometries()[:9] {
+		doc := rawDocument(t, g)
+
+		got, err := DecodeGeometryRaw(doc.Lookup("location"))
+		if err != nil {
+			t.Fatalf("should decode %s just fine but got %v", g.Type, err)
+		}
+
+		if !reflect.DeepEqual(got, g) {
+			t.Errorf("incorrect geometry, got %+v, want %+v", got, g)
+		}
+	}
+
+	if _, err := DecodeGeometryRaw(rawDocument(t, 1).Lookup("location")); err == nil {
+		t.Errorf("should fail to decode an integer")
+	}
+}
+
+func TestRawGeometry(t *testing.T) {
+	g := NewGeometryCollection(
+		NewPoint(Point{1, 2}),
+		NewMultiPolygon(
+			[][]Point{{{170, 0}, {-170, 0}, {-170, 10}, {170, 0}}},
+			[][]Point{{{175, 1}, {176, 1}, {176, 2}, {175, 1}}, {{175.5, 1.2}, {175.6, 1.2}, {175.6, 1.3}, {175.5, 1.2}}},
+		),
+	)
+
+	raw, err := NewRawGeometry(rawDocument(t, g).Lookup("location"))
+	if err != nil {
+		t.Fatalf("should create raw geometry just fine but got %v", err)
+	}
+
+	if typ, err := raw.Type(); err != nil || typ != GeometryCollection {
+		t.Errorf("incorrect type, got %v, %v", typ, err)
+	}
+
+	if n, err := raw.NumPositions(); err != nil || n != 13 {
+		t.Errorf("incorrect number of positions, got %v, %v", n, err)
+	}
+
+	var positions []Point
+	err = raw.Positions(func(p Point) bool {
+		positions = append(positions, append(Point(nil), p...))
+		return len(positions) < 3
+	})
+	if err != nil || !reflect.DeepEqual(positions, []Point{{1, 2}, {170, 0}, {-170, 0}}) {
+		t.Errorf("incorrect positions, got %v, %v", positions, err)
+	}
+
+	bound, err := raw.Bound()
+	if err != nil || !reflect.DeepEqual(bound, g.Bound()) {
+		t.Errorf("incorrect bound, got %v, want %v, %v", bound, g.Bound(), err)
+	}
+
+	members := 0
+	if err = raw.Geometries(func(RawGeometry) bool { members++; return true }); err != nil || members != 2 {
+		t.Errorf("incorrect geometries, got %d, %v", members, err)
+	}
+
+	decoded, err := raw.Decode()
+	if err != nil || !reflect.DeepEqual(decoded, g) {
+		t.Errorf("incorrect decoded geometry, got %+v, %v", decoded, err)
+	}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_, _ = raw.NumPositions()
+	})
+	if allocs > 4 {
+		t.Errorf("counting positions should barely allocate, got %v allocations", allocs)
+	}
+}
+
+func TestRawGeometryInvalid(t *testing.T) {
+	cases := []interface{}{
+		bson.D{{Key: "coordinates", Value: bson.A{1, 2}}},
+		bson.D{{Key: "type", Value: "Point"}},
+		bson.D{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: bson.A{"1", 2}}},
+		bson.D{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: 1}},
+		bson.D{{Key: "type", Value: "GeometryCollection"}, {Key: "geometries", Value: bson.A{1}}},
+	}
+
+	for _, c := range cases {
+		raw, err := NewRawGeometry(rawDocument(t, c).Lookup("location"))
+		if err != nil {
+			t.Fatalf("should create raw geometry just fine but got %v", err)
+		}
+
+		if _, err := raw.NumPositions(); err == nil {
+			t.Errorf("should fail to walk %v", c)
+		}
+	}
+
+	if _, err := NewRawGeometry(rawDocument(t, "Point").Lookup("location")); err == nil {
+		t.Errorf("should fail to create a raw geometry from a string")
+	}
+}
+
+func TestRawGeometryNesting(t *testing.T) {
+	line := bson.A{bson.A{1, 2}, bson.A{3, 4}}
+	cases := []bson.D{
+		{{Key: "type", Value: "Point"}, {Key: "coordinates", Value: line}},
+		{{Key: "type", Value: "LineString"}, {Key: "coordinates", Value: bson.A{1, 2}}},
+		{{Key: "type", Value: "Polygon"}, {Key: "coordinates", Value: line}},
+		{{Key: "type", Value: "MultiPolygon"}, {Key: "coordinates", Value: bson.A{line}}},
+		{{Key: "type", Value: "MultiPoint"}, {Key: "coordinates", Value: bson.A{line}}},
+	}
+
+	for _, c := range cases {
+		doc := rawDocument(t, c)
+		raw, _ := NewRawGeometry(doc.Lookup("location"))
+
+		_, err := raw.NumPositions()
+		var e *DecodeError
+		if !errors.As(err, &e) {
+			t.Fatalf("should fail to walk %v with a DecodeError but got %v", c, err)
+		}
+
+		_, want := UnmarshalGeometry(doc.Lookup("location").Document())
+		if err.Error() != want.Error() {
+			t.Errorf("should fail like decodeGeometry, got %v, want %v", err, want)
+		}
+	}
+}
+
+func BenchmarkRawGeometryBound(b *testing.B) {
+	raw, _ := NewRawGeometry(rawDocument(b, benchmarkPolygon()).Lookup("location"))
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := raw.Bound(); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
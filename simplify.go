@@ -0,0 +1,334 @@
+package geojson
+
+import (
+	"container/heap"
+	"math"
+)
+
+// Simplifier selects the algorithm used to remove positions from lines and rings.
+type Simplifier int
+
+const (
+	// DouglasPeucker keeps the positions lying farther than the tolerance, in degrees,
+	// from the line joining the positions kept around them.
+	DouglasPeucker Simplifier = iota
+
+	// VisvalingamWhyatt repeatedly removes the position forming the triangle of
+	// smallest area with its two neighbours, until every triangle has an area of
+	// at least the tolerance squared, in square degrees.
+	// It tends to keep the overall shape better than DouglasPeucker.
+	VisvalingamWhyatt
+)
+
+// simplifyRetries is how many times a polygon that became invalid is simplified
+// again with half the tolerance before its original rings are kept.
+const simplifyRetries = 8
+
+// Simplify returns a copy of the geometry with fewer positions, using
+// DouglasPeucker with the tolerance in degrees, see Simplifier.Simplify.
+func (g *Geometry) Simplify(tolerance float64) *Geometry {
+	return DouglasPeucker.Simplify(g, tolerance)
+}
+
+// Simplify returns a copy of the geometry with the lines and rings of its
+// LineString, MultiLineString, Polygon and MultiPolygon members simplified,
+// collections are simplified recursively and points are copied as is.
+// Positions are compared in the lon/lat plane, the copy shares its positions with g.
+// Lines keep their end positions and at least 2 positions. Rings stay closed with
+// at least 4 positions, and a polygon stays valid: when its simplified rings
+// intersect each other or a hole leaves the shell, the polygon is simplified again
+// with a smaller tolerance, down to keeping its original rings.
+func (s Simplifier) Simplify(g *Geometry, tolerance float64) *Geometry {
+	if g == nil {
+		return nil
+	}
+
+	simplified := &Geometry{Type: g.Type, BBox: g.BBox, CRS: g.CRS, Raw: g.Raw}
+	switch g.Type {
+	case GeometryPoint:
+		simplified.Point = g.Point
+	case GeometryMultiPoint:
+		simplified.MultiPoint = append([]Point(nil), g.MultiPoint...)
+	case GeometryLineString:
+		simplified.LineString = s.line(g.LineString, tolerance)
+	case GeometryMultiLineString:
+		simplified.MultiLineString = make([][]Point, len(g.MultiLineString))
+		for i, line := range g.MultiLineString {
+			simplified.MultiLineString[i] = s.line(line, tolerance)
+		}
+	case GeometryPolygon:
+		simplified.Polygon = s.polygon(g.Polygon, tolerance)
+	case GeometryMultiPolygon:
+		simplified.MultiPolygon = make([][][]Point, len(g.MultiPolygon))
+		for i, polygon := range g.MultiPolygon {
+			simplified.MultiPolygon[i] = s.polygon(polygon, tolerance)
+		}
+	case GeometryCollection:
+		if g.Geometries != nil {
+			simplified.Geometries = make([]*Geometry, len(g.Geometries))
+		}
+		for i, geometry := range g.Geometries {
+			simplified.Geometries[i] = s.Simplify(geometry, tolerance)
+		}
+	}
+
+	return simplified
+}
+
+func (s Simplifier) line(line []Point, tolerance float64) []Point {
+	if line == nil {
+		return nil
+	}
+
+	if len(line) < 3 || !(tolerance > 0) {
+		return append([]Point(nil), line...)
+	}
+
+	keep := make([]bool, len(line))
+	keep[0], keep[len(line)-1] = true, true
+	s.mark(line, keep, tolerance, 2)
+
+	return keptPositions(line, keep)
+}
+
+func (s Simplifier) polygon(polygon [][]Point, tolerance float64) [][]Point {
+	if polygon == nil {
+		return nil
+	}
+
+	rings := make([][]Point, len(polygon))
+	for try := 0; try < simplifyRetries && tolerance > 0; try++ {
+		for i, ring := range polygon {
+			rings[i] = s.ring(ring, tolerance)
+		}
+
+		if polygonValid(rings) {
+			return rings
+		}
+		tolerance /= 2
+	}
+
+	for i, ring := range polygon {
+		rings[i] = append([]Point(nil), ring...)
+	}
+
+	return rings
+}
+
+// ring simplifies a closed ring, the position farthest from the first one is
+// always kept so the ring cannot collapse.
+func (s Simplifier) ring(ring []Point, tolerance float64) []Point {
+	if len(ring) < 5 || !pointEqual(ring[0], ring[len(ring)-1]) {
+		return append([]Point(nil), ring...)
+	}
+
+	far, max := 0, -1.0
+	for i, p := range ring {
+		if d := planarDistance(ring[0], p); d > max {
+			far, max = i, d
+		}
+	}
+
+	keep := make([]bool, len(ring))
+	keep[0], keep[far], keep[len(ring)-1] = true, true, true
+	s.mark(ring[:far+1], keep[:far+1], tolerance, 2)
+	s.mark(ring[far:], keep[far:], tolerance, 2)
+
+	if kept := keptPositions(ring, keep); len(kept) >= 4 {
+		return kept
+	}
+
+	// only the first and the farthest positions are left, add the one farthest
+	// from the segment joining them
+	third, max := 0, 0.0
+	for i, p := range ring {
+		if d := segmentDistance(ring[0], ring[far], p); d > max {
+			third, max = i, d
+		}
+	}
+	if max == 0 {
+		return append([]Point(nil), ring...)
+	}
+	keep[third] = true
+
+	return keptPositions(ring, keep)
+}
+
+// mark sets keep for the positions of the line to keep, the end positions are
+// always kept and at least min positions are kept.
+func (s Simplifier) mark(line []Point, keep []bool, tolerance float64, min int) {
+	if len(line) < 3 {
+		return
+	}
+
+	switch s {
+	case VisvalingamWhyatt:
+		visvalingamWhyatt(line, keep, tolerance*tolerance, min)
+	default:
+		douglasPeucker(line, keep, tolerance)
+	}
+}
+
+func douglasPeucker(line []Point, keep []bool, tolerance float64) {
+	stack := [][2]int{{0, len(line) - 1}}
+	for len(stack) > 0 {
+		first, last := stack[len(stack)-1][0], stack[len(stack)-1][1]
+		stack = stack[:len(stack)-1]
+
+		index, max := 0, 0.0
+		for i := first + 1; i < last; i++ {
+			if d := segmentDistance(line[first], line[last], line[i]); d > max {
+				index, max = i, d
+			}
+		}
+
+		if max > tolerance {
+			keep[index] = true
+			stack = append(stack, [2]int{first, index}, [2]int{index, last})
+		}
+	}
+}
+
+// visvalingamWhyatt removes the positions by increasing area of their triangle,
+// the areas of the neighbours are updated after each removal.
+func visvalingamWhyatt(line []Point, keep []bool, threshold float64, min int) {
+	n := len(line)
+	prev, next := make([]int, n), make([]int, n)
+	h := make(triangleHeap, 0, n-2)
+	for i := range line {
+		prev[i], next[i] = i-1, i+1
+		keep[i] = true
+		if i > 0 && i < n-1 {
+			h = append(h, &triangle{index: i, area: triangleArea(line[i-1], line[i], line[i+1]), heapIndex: len(h)})
+		}
+	}
+	heap.Init(&h)
+
+	// triangles holds the triangle of every position still in the line, nil at the ends
+	triangles := make([]*triangle, n)
+	for _, t := range h {
+		triangles[t.index] = t
+	}
+
+	remaining := n
+	for h.Len() > 0 && remaining > min {
+		t := heap.Pop(&h).(*triangle)
+		if t.area >= threshold {
+			break
+		}
+
+		keep[t.index] = false
+		remaining--
+		p, q := prev[t.index], next[t.index]
+		next[p], prev[q] = q, p
+
+		// the area of a neighbour never drops below the removed one, so the
+		// positions are removed in order of their effective area
+		for _, i := range []int{p, q} {
+			if neighbour := triangles[i]; neighbour != nil {
+				neighbour.area = math.Max(triangleArea(line[prev[i]], line[i], line[next[i]]), t.area)
+				heap.Fix(&h, neighbour.heapIndex)
+			}
+		}
+		triangles[t.index] = nil
+	}
+}
+
+type triangle struct {
+	index     int
+	area      float64
+	heapIndex int
+}
+
+// triangleHeap is a min-heap of triangles by area.
+type triangleHeap []*triangle
+
+func (h triangleHeap) Len() int           { return len(h) }
+func (h triangleHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h triangleHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex, h[j].heapIndex = i, j
+}
+
+func (h *triangleHeap) Push(x interface{}) {
+	t := x.(*triangle)
+	t.heapIndex = len(*h)
+	*h = append(*h, t)
+}
+
+func (h *triangleHeap) Pop() interface{} {
+	old := *h
+	t := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return t
+}
+
+func keptPositions(line []Point, keep []bool) []Point {
+	kept := make([]Point, 0, len(line))
+	for i, p := range line {
+		if keep[i] {
+			kept = append(kept, p)
+		}
+	}
+
+	return kept
+}
+
+// polygonValid reports whether the rings are simple, the holes lie inside the shell
+// and no two holes cross each other, in the lon/lat plane.
+func polygonValid(rings [][]Point) bool {
+	for _, ring := range rings {
+		if _, ok := ringSimple(ring); !ok {
+			return false
+		}
+	}
+
+	for i, hole := range rings[1:] {
+		if _, ok := ringWithin(hole, rings[0]); !ok {
+			return false
+		}
+
+		for _, other := range rings[i+2:] {
+			if ringsCross(hole, other) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// ringsCross reports whether two rings cross each other or one lies inside the other.
+func ringsCross(a, b []Point) bool {
+	for i := 0; i+1 < len(a); i++ {
+		for j := 0; j+1 < len(b); j++ {
+			if segmentsCross(a[i], a[i+1], b[j], b[j+1]) {
+				return true
+			}
+		}
+	}
+
+	return ringContains(a, b[0]) > 0 || ringContains(b, a[0]) > 0
+}
+
+func planarDistance(a, b Point) float64 {
+	return math.Hypot(b[0]-a[0], b[1]-a[1])
+}
+
+// segmentDistance returns the distance from p to the segment ab in the lon/lat plane.
+func segmentDistance(a, b, p Point) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	length := dx*dx + dy*dy
+	if length == 0 {
+		return planarDistance(a, p)
+	}
+
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / length
+	t = math.Max(0, math.Min(1, t))
+
+	return math.Hypot(p[0]-a[0]-t*dx, p[1]-a[1]-t*dy)
+}
+
+func triangleArea(a, b, c Point) float64 {
+	return math.Abs(cross(a, b, c)) / 2
+}
@@ -0,0 +1,100 @@
+package geojson
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+// wavyRing returns a closed ring around (0, 0) of n positions with a small wave
+// on its radius.
+func wavyRing(radius, wave float64, n int) []Point {
+	ring := make([]Point, 0, n+1)
+	for i := 0; i < n; i++ {
+		a := 2 * math.Pi * float64(i) / float64(n)
+		r := radius + wave*math.Sin(40*a)
+		ring = append(ring, Point{r * math.Cos(a), r * math.Sin(a)})
+	}
+
+	return append(ring, ring[0])
+}
+
+func TestSimplifyLine(t *testing.T) {
+	line := []Point{{0, 0}, {1, 0.01}, {2, -0.01}, {3, 0}, {3, 1}, {3.01, 2}, {3, 3}}
+
+	for s, tolerance := range map[Simplifier]float64{DouglasPeucker: 0.1, VisvalingamWhyatt: 0.5} {
+		g := s.Simplify(NewLineString(line), tolerance)
+		expected := []Point{{0, 0}, {3, 0}, {3, 3}}
+		if !reflect.DeepEqual(g.LineString, expected) {
+			t.Errorf("incorrect line for %d, got %v", s, g.LineString)
+		}
+	}
+
+	if g := NewLineString(line).Simplify(0); !reflect.DeepEqual(g.LineString, line) {
+		t.Errorf("should not simplify with no tolerance, got %v", g.LineString)
+	}
+
+	g := NewLineString([]Point{{0, 0}, {1, 0}, {2, 0}}).Simplify(1)
+	if !reflect.DeepEqual(g.LineString, []Point{{0, 0}, {2, 0}}) {
+		t.Errorf("should keep the end positions, got %v", g.LineString)
+	}
+
+	if g := (*Geometry)(nil).Simplify(1); g != nil {
+		t.Errorf("should simplify nil to nil, got %v", g)
+	}
+}
+
+func TestSimplifyPolygon(t *testing.T) {
+	shell := wavyRing(10, 0.05, 2000)
+	hole := wavyRing(9.9, 0.05, 500)
+	polygon := NewPolygon([][]Point{shell, hole})
+
+	for _, s := range []Simplifier{DouglasPeucker, VisvalingamWhyatt} {
+		for _, tolerance := range []float64{0.01, 0.5, 5, 100} {
+			g := s.Simplify(polygon, tolerance)
+			if err := g.Validate(); err != nil {
+				t.Errorf("should stay valid for %d with %v but got %v", s, tolerance, err)
+			}
+
+			if len(g.Polygon) != 2 || len(g.Polygon[0]) > len(shell) || len(g.Polygon[1]) > len(hole) {
+				t.Errorf("incorrect simplified polygon for %d with %v", s, tolerance)
+			}
+		}
+
+		g := s.Simplify(polygon, 0.5)
+		if len(g.Polygon[0]) >= len(shell) {
+			t.Errorf("should simplify the shell for %d, got %d positions", s, len(g.Polygon[0]))
+		}
+	}
+
+	// a small island collapses to a triangle
+	g := NewPolygon([][]Point{wavyRing(0.001, 0.0001, 100)}).Simplify(1)
+	if len(g.Polygon[0]) != 4 || !pointEqual(g.Polygon[0][0], g.Polygon[0][3]) {
+		t.Errorf("should keep a closed triangle, got %v", g.Polygon[0])
+	}
+}
+
+func TestSimplifyCollection(t *testing.T) {
+	line := []Point{{0, 0}, {1, 0.01}, {2, 0}}
+	g := NewGeometryCollection(
+		NewPoint(Point{1, 2}),
+		NewMultiLineString(line, line),
+		NewMultiPolygon([][]Point{wavyRing(10, 0.05, 200)}),
+	).Simplify(0.1)
+
+	if !reflect.DeepEqual(g.Geometries[0], NewPoint(Point{1, 2})) {
+		t.Errorf("incorrect point, got %v", g.Geometries[0])
+	}
+
+	if len(g.Geometries[1].MultiLineString[1]) != 2 {
+		t.Errorf("incorrect lines, got %v", g.Geometries[1].MultiLineString)
+	}
+
+	if err := g.Geometries[2].Validate(); err != nil || len(g.Geometries[2].MultiPolygon[0][0]) >= 201 {
+		t.Errorf("incorrect polygons, got %v", err)
+	}
+
+	if len(line) != 3 {
+		t.Errorf("should not modify the original line, got %v", line)
+	}
+}
@@ -0,0 +1,482 @@
+package geojson
+
+import (
+	"math"
+	"sort"
+)
+
+// The spatial predicates work on the unit sphere with great circle edges, the
+// same model a MongoDB 2dsphere index uses, see predicate.go.
+
+// sphereEpsilon is the angular tolerance, in radians, under which two positions are
+// considered equal or a position is considered to lie on an edge, about 6 micrometers.
+const sphereEpsilon = 1e-12
+
+// sphereNudge is the angular distance, in radians, a position is moved off an edge
+// to sample the regions on both of its sides, about 6 millimeters.
+const sphereNudge = 1e-9
+
+// vector is a position on the unit sphere.
+type vector [3]float64
+
+func toVector(p Point) vector {
+	slon, clon := sincosd(p[0])
+	slat, clat := sincosd(p[1])
+	return vector{clat * clon, clat * slon, slat}
+}
+
+func (v vector) add(w vector) vector {
+	return vector{v[0] + w[0], v[1] + w[1], v[2] + w[2]}
+}
+
+func (v vector) sub(w vector) vector {
+	return vector{v[0] - w[0], v[1] - w[1], v[2] - w[2]}
+}
+
+func (v vector) scale(s float64) vector {
+	return vector{v[0] * s, v[1] * s, v[2] * s}
+}
+
+func (v vector) dot(w vector) float64 {
+	return v[0]*w[0] + v[1]*w[1] + v[2]*w[2]
+}
+
+func (v vector) cross(w vector) vector {
+	return vector{
+		v[1]*w[2] - v[2]*w[1],
+		v[2]*w[0] - v[0]*w[2],
+		v[0]*w[1] - v[1]*w[0],
+	}
+}
+
+func (v vector) norm() float64 {
+	return math.Sqrt(v.dot(v))
+}
+
+func (v vector) normalize() vector {
+	n := v.norm()
+	if n == 0 {
+		return v
+	}
+	return v.scale(1 / n)
+}
+
+func (v vector) near(w vector) bool {
+	return v.sub(w).norm() <= sphereEpsilon
+}
+
+// angle returns the angle between v and w in radians.
+func (v vector) angle(w vector) float64 {
+	return math.Atan2(v.cross(w).norm(), v.dot(w))
+}
+
+// ortho returns a unit vector perpendicular to v.
+func (v vector) ortho() vector {
+	w := vector{1, 0, 0}
+	if math.Abs(v[0]) > math.Abs(v[1]) {
+		w = vector{0, 1, 0}
+	}
+	return v.cross(w).normalize()
+}
+
+// onArc returns true if p lies on the shortest great circle arc from a to b.
+func onArc(p, a, b vector) bool {
+	if p.near(a) || p.near(b) {
+		return true
+	}
+
+	n := a.cross(b)
+	l := n.norm()
+	if l < sphereEpsilon {
+		// degenerate edge
+		return false
+	}
+
+	if math.Abs(n.dot(p))/l > sphereEpsilon {
+		return false
+	}
+
+	return a.cross(p).dot(n) > 0 && p.cross(b).dot(n) > 0
+}
+
+// arcsCross returns true if the arcs ab and cd cross at a point interior to both.
+func arcsCross(a, b, c, d vector) bool {
+	n1, n2 := a.cross(b), c.cross(d)
+	sa, sb := n2.dot(a), n2.dot(b)
+	sc, sd := n1.dot(c), n1.dot(d)
+
+	// the great circles meet at n1 x n2 and its antipode, the arcs cross if
+	// both contain the same one of them.
+	return (sa > 0 && sb < 0 && sc < 0 && sd > 0) || (sa < 0 && sb > 0 && sc > 0 && sd < 0)
+}
+
+// arcsIntersect returns true if the arcs ab and cd share at least one point.
+func arcsIntersect(a, b, c, d vector) bool {
+	return onArc(a, c, d) || onArc(b, c, d) || onArc(c, a, b) || onArc(d, a, b) || arcsCross(a, b, c, d)
+}
+
+// crossingPoint returns the point where the arcs ab and cd cross, see arcsCross.
+func crossingPoint(a, b, c, d vector) vector {
+	x := a.cross(b).cross(c.cross(d)).normalize()
+	if c.cross(d).dot(a) < 0 {
+		return x.scale(-1)
+	}
+	return x
+}
+
+// splitArc returns the positions where the arc ab meets the edges and vertices
+// of the shape, sorted from a to b, a and b included.
+func splitArc(a, b vector, s *sphereShape) []vector {
+	points := []vector{a, b}
+	for _, edges := range [][][2]vector{s.lineEdges, s.ringEdges} {
+		for _, e := range edges {
+			if arcsCross(a, b, e[0], e[1]) {
+				points = append(points, crossingPoint(a, b, e[0], e[1]))
+			}
+		}
+	}
+
+	for _, v := range s.vertices {
+		if onArc(v, a, b) {
+			points = append(points, v)
+		}
+	}
+
+	sort.Slice(points, func(i, j int) bool {
+		return a.angle(points[i]) < a.angle(points[j])
+	})
+
+	return points
+}
+
+// sphereRing is a closed ring of great circle edges, the last vertex is not repeated.
+type sphereRing struct {
+	vertices []vector
+
+	// leftInterior is true if the ring encloses the region on its left, walking
+	// its vertices in order: when that region is the smaller of the two, or always
+	// with the strict winding CRS.
+	leftInterior bool
+
+	// ref is a position known to be on the left of the ring.
+	ref vector
+}
+
+// newSphereRing creates the ring, with strict it encloses the region on its left
+// whatever its size.
+func newSphereRing(ring []Point, strict bool) *sphereRing {
+	ring = openRing(ring)
+	r := &sphereRing{}
+	for _, p := range ring {
+		if len(p) >= 2 {
+			r.vertices = append(r.vertices, toVector(p))
+		}
+	}
+
+	if len(r.vertices) < 3 {
+		return r
+	}
+
+	r.leftInterior = strict || Spherical.ringArea(ring) > 0
+
+	// the reference position is moved off the middle of the longest edge, to its left
+	longest, length := 0, 0.0
+	for i, a := range r.vertices {
+		if l := a.angle(r.vertices[(i+1)%len(r.vertices)]); l > length {
+			longest, length = i, l
+		}
+	}
+	a, b := r.vertices[longest], r.vertices[(longest+1)%len(r.vertices)]
+	r.ref = a.add(b).normalize().add(a.cross(b).normalize().scale(math.Min(1e-7, length/100))).normalize()
+
+	return r
+}
+
+// onBoundary returns true if p lies on an edge of the ring.
+func (r *sphereRing) onBoundary(p vector) bool {
+	for i, a := range r.vertices {
+		if onArc(p, a, r.vertices[(i+1)%len(r.vertices)]) {
+			return true
+		}
+	}
+	return false
+}
+
+// contains returns true if p lies in the region enclosed by the ring,
+// p must not lie on the ring.
+func (r *sphereRing) contains(p vector) bool {
+	if len(r.vertices) < 3 {
+		return false
+	}
+
+	left := r.leftContains(p)
+	if r.leftInterior {
+		return left
+	}
+	return !left
+}
+
+// leftContains counts the edges crossed from the reference position to p.
+func (r *sphereRing) leftContains(p vector) bool {
+	origin := r.ref
+	crossings := 0
+	if origin.dot(p) < 0 {
+		// go through a position halfway to keep both arcs shorter than a half circle
+		w := origin.cross(p)
+		if w.norm() < sphereEpsilon {
+			w = origin.ortho()
+		}
+		mid := w.cross(origin).normalize()
+		crossings += r.crossings(origin, mid)
+		origin = mid
+	}
+	crossings += r.crossings(origin, p)
+
+	return crossings%2 == 0
+}
+
+// crossings returns the number of edges crossed by the arc ab. A vertex lying on the
+// great circle of the arc is counted on its left side so it is crossed only once.
+func (r *sphereRing) crossings(a, b vector) int {
+	n1 := a.cross(b)
+	count := 0
+	for i, c := range r.vertices {
+		d := r.vertices[(i+1)%len(r.vertices)]
+		sc, sd := n1.dot(c) >= 0, n1.dot(d) >= 0
+		if sc == sd {
+			continue
+		}
+
+		n2 := c.cross(d)
+		sa, sb := n2.dot(a) > 0, n2.dot(b) > 0
+		if sa == sb {
+			continue
+		}
+
+		// same crossing point on both arcs, see arcsCross
+		if sa == sd {
+			count++
+		}
+	}
+
+	return count
+}
+
+// The location of a position relative to a shape.
+const (
+	locExterior = iota
+	locBoundary
+	locInterior
+)
+
+// spherePolygon is an exterior ring followed by its holes.
+type spherePolygon []*sphereRing
+
+func (pg spherePolygon) locate(p vector) int {
+	for _, r := range pg {
+		if r.onBoundary(p) {
+			return locBoundary
+		}
+	}
+
+	if len(pg) == 0 || !pg[0].contains(p) {
+		return locExterior
+	}
+
+	for _, hole := range pg[1:] {
+		if hole.contains(p) {
+			return locExterior
+		}
+	}
+
+	return locInterior
+}
+
+// sphereShape is a geometry flattened into points, lines and polygons on the sphere.
+type sphereShape struct {
+	points   []vector
+	lines    [][]vector
+	polygons []spherePolygon
+
+	lineEdges [][2]vector
+	ringEdges [][2]vector
+	vertices  []vector
+}
+
+func newSphereShape(g *Geometry) *sphereShape {
+	s := &sphereShape{}
+	s.addGeometry(g)
+	return s
+}
+
+func (s *sphereShape) addGeometry(g *Geometry) {
+	if g == nil {
+		return
+	}
+
+	switch g.Type {
+	case GeometryPoint:
+		s.addPoint(g.Point)
+	case GeometryMultiPoint:
+		for _, p := range g.MultiPoint {
+			s.addPoint(p)
+		}
+	case GeometryLineString:
+		s.addLine(g.LineString)
+	case GeometryMultiLineString:
+		for _, line := range g.MultiLineString {
+			s.addLine(line)
+		}
+	case GeometryPolygon:
+		s.addPolygon(g.Polygon, g.CRS.IsStrictWinding())
+	case GeometryMultiPolygon:
+		for _, polygon := range g.MultiPolygon {
+			s.addPolygon(polygon, g.CRS.IsStrictWinding())
+		}
+	case GeometryCollection:
+		for _, geometry := range g.Geometries {
+			s.addGeometry(geometry)
+		}
+	}
+}
+
+func (s *sphereShape) addPoint(p Point) {
+	if len(p) >= 2 {
+		v := toVector(p)
+		s.points = append(s.points, v)
+		s.vertices = append(s.vertices, v)
+	}
+}
+
+func (s *sphereShape) addLine(line []Point) {
+	var vertices []vector
+	for _, p := range line {
+		if len(p) >= 2 {
+			vertices = append(vertices, toVector(p))
+		}
+	}
+
+	if len(vertices) == 0 {
+		return
+	}
+
+	s.lines = append(s.lines, vertices)
+	s.vertices = append(s.vertices, vertices...)
+	for i := 1; i < len(vertices); i++ {
+		s.lineEdges = append(s.lineEdges, [2]vector{vertices[i-1], vertices[i]})
+	}
+}
+
+// addPolygon adds the polygon, with strict its exterior ring encloses the region
+// on its left, the holes always enclose the smaller region.
+func (s *sphereShape) addPolygon(polygon [][]Point, strict bool) {
+	var pg spherePolygon
+	for i, ring := range polygon {
+		r := newSphereRing(ring, strict && i == 0)
+		if len(r.vertices) == 0 {
+			continue
+		}
+
+		pg = append(pg, r)
+		s.vertices = append(s.vertices, r.vertices...)
+		for i, a := range r.vertices {
+			s.ringEdges = append(s.ringEdges, [2]vector{a, r.vertices[(i+1)%len(r.vertices)]})
+		}
+	}
+
+	if len(pg) > 0 {
+		s.polygons = append(s.polygons, pg)
+	}
+}
+
+func (s *sphereShape) isEmpty() bool {
+	return len(s.vertices) == 0
+}
+
+// locate returns whether p lies in the exterior, on the boundary or in the interior
+// of the shape. Points have no boundary, the boundary of a line is its two ends
+// unless it is closed, the boundary of a polygon is its rings.
+func (s *sphereShape) locate(p vector) int {
+	loc := locExterior
+	for _, v := range s.points {
+		if p.near(v) {
+			return locInterior
+		}
+	}
+
+	for _, line := range s.lines {
+		if l := locateOnLine(p, line); l == locInterior {
+			return locInterior
+		} else if l > loc {
+			loc = l
+		}
+	}
+
+	for _, pg := range s.polygons {
+		if l := pg.locate(p); l == locInterior {
+			return locInterior
+		} else if l > loc {
+			loc = l
+		}
+	}
+
+	return loc
+}
+
+func locateOnLine(p vector, line []vector) int {
+	first, last := line[0], line[len(line)-1]
+	closed := first.near(last)
+	for i := 1; i < len(line); i++ {
+		if !onArc(p, line[i-1], line[i]) {
+			continue
+		}
+
+		if !closed && (p.near(first) || p.near(last)) {
+			return locBoundary
+		}
+		return locInterior
+	}
+
+	if len(line) == 1 && p.near(first) {
+		return locInterior
+	}
+
+	return locExterior
+}
+
+// edgeSamples calls visit with the positions along the arc ab split by the other
+// shape: the split positions and the middle of every piece. When sides is set,
+// the middles are also moved off the arc to both of its sides.
+// It stops and returns true as soon as visit does.
+func edgeSamples(a, b vector, other *sphereShape, sides bool, visit func(vector) bool) bool {
+	points := splitArc(a, b, other)
+	for i, x := range points {
+		if visit(x) {
+			return true
+		}
+
+		if i == 0 {
+			continue
+		}
+
+		w := points[i-1]
+		length := w.angle(x)
+		if length < sphereEpsilon {
+			continue
+		}
+
+		mid := w.add(x).normalize()
+		if visit(mid) {
+			return true
+		}
+
+		if sides {
+			offset := a.cross(b).normalize().scale(math.Min(sphereNudge, length/4))
+			if visit(mid.add(offset).normalize()) || visit(mid.sub(offset).normalize()) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,371 @@
+package geojson
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// recordSeparator starts every GeoJSON text of an RFC 8142 GeoJSON Text Sequence.
+// https://tools.ietf.org/html/rfc8142
+const recordSeparator = 0x1e
+
+// A Decoder reads features and geometries one at a time from a stream of
+// RFC 7946 GeoJSON, only the object being decoded is kept in memory.
+// The stream holds any number of features, geometries and feature collections,
+// one after the other, separated by white space as in newline-delimited GeoJSON,
+// or by record separators as in an RFC 8142 GeoJSON Text Sequence.
+// The features of a feature collection are decoded one by one, the collection
+// itself is never returned.
+type Decoder struct {
+	dec *json.Decoder
+
+	// Opts is used to decode the geometries, those of the features included.
+	Opts DecodeOptions
+
+	// inFeatures is true while reading the features array of a feature collection
+	inFeatures bool
+	// collectionType is true once the type member of the current collection was checked
+	collectionType bool
+}
+
+// NewDecoder returns a decoder reading from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{dec: json.NewDecoder(&sequenceReader{r: r})}
+}
+
+// Decode returns the next feature of the stream, a geometry is returned as a
+// feature without properties. It returns io.EOF at the end of the stream.
+func (d *Decoder) Decode() (*Feature, error) {
+	object, err := d.next()
+	if err != nil {
+		return nil, err
+	}
+
+	if object["type"] == "Feature" {
+		f := &Feature{}
+		if err := decodeFeature(f, object, d.Opts); err != nil {
+			return nil, err
+		}
+		return f, nil
+	}
+
+	g := &Geometry{}
+	if err := decodeGeometry(g, object, d.Opts, 0); err != nil {
+		return nil, err
+	}
+
+	return NewFeature(g), nil
+}
+
+// DecodeGeometry returns the geometry of the next feature of the stream, or the
+// next geometry. It returns io.EOF at the end of the stream.
+func (d *Decoder) DecodeGeometry() (*Geometry, error) {
+	f, err := d.Decode()
+	if err != nil {
+		return nil, err
+	}
+
+	return f.Geometry, nil
+}
+
+// next returns the next object of the stream other than a feature collection.
+func (d *Decoder) next() (map[string]interface{}, error) {
+	for {
+		if d.inFeatures {
+			if d.dec.More() {
+				return d.decodeObject()
+			}
+
+			if err := d.endCollection(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		tok, err := d.dec.Token()
+		if err != nil {
+			return nil, err
+		}
+
+		if tok != json.Delim('{') {
+			return nil, fmt.Errorf("not a valid GeoJSON object, got %v", tok)
+		}
+
+		object, err := d.readMembers()
+		if err != nil || object != nil {
+			return object, err
+		}
+	}
+}
+
+// readMembers reads the members of an object whose opening brace was consumed.
+// It returns the object, or nil when the object is a feature collection whose
+// features array was reached.
+func (d *Decoder) readMembers() (map[string]interface{}, error) {
+	members := make(map[string]json.RawMessage)
+	for d.dec.More() {
+		key, err := d.memberKey()
+		if err != nil {
+			return nil, err
+		}
+
+		if key == "features" {
+			if t, ok := members["type"]; ok {
+				if err := checkCollectionType(t); err != nil {
+					return nil, err
+				}
+				d.collectionType = true
+			}
+
+			tok, err := d.dec.Token()
+			if err != nil {
+				return nil, err
+			}
+			if tok != json.Delim('[') {
+				return nil, fmt.Errorf("not a valid set of features, got %v", tok)
+			}
+
+			d.inFeatures = true
+			return nil, nil
+		}
+
+		var value json.RawMessage
+		if err := d.dec.Decode(&value); err != nil {
+			return nil, err
+		}
+		members[key] = value
+	}
+
+	if _, err := d.dec.Token(); err != nil {
+		return nil, err
+	}
+
+	data, err := json.Marshal(members)
+	if err != nil {
+		return nil, err
+	}
+
+	object, err := decodeJSONObject(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if object["type"] == "FeatureCollection" {
+		return nil, fmt.Errorf("not a valid set of features, got %v", object["features"])
+	}
+
+	return object, nil
+}
+
+// endCollection reads the end of the features array and the remaining members of the collection.
+func (d *Decoder) endCollection() error {
+	if _, err := d.dec.Token(); err != nil {
+		return err
+	}
+	d.inFeatures = false
+
+	for d.dec.More() {
+		key, err := d.memberKey()
+		if err != nil {
+			return err
+		}
+
+		var value json.RawMessage
+		if err := d.dec.Decode(&value); err != nil {
+			return err
+		}
+
+		if key == "type" {
+			if err := checkCollectionType(value); err != nil {
+				return err
+			}
+			d.collectionType = true
+		}
+	}
+
+	if _, err := d.dec.Token(); err != nil {
+		return err
+	}
+
+	if !d.collectionType {
+		return errors.New("type property not defined")
+	}
+	d.collectionType = false
+
+	return nil
+}
+
+func (d *Decoder) memberKey() (string, error) {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return "", err
+	}
+
+	key, ok := tok.(string)
+	if !ok {
+		return "", fmt.Errorf("not a valid member name, got %v", tok)
+	}
+
+	return key, nil
+}
+
+func (d *Decoder) decodeObject() (map[string]interface{}, error) {
+	var value json.RawMessage
+	if err := d.dec.Decode(&value); err != nil {
+		return nil, err
+	}
+
+	object, err := decodeJSONObject(value)
+	if err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return nil, errors.New("not a valid feature, got null")
+	}
+
+	return object, nil
+}
+
+func checkCollectionType(data json.RawMessage) error {
+	var t interface{}
+	if err := json.Unmarshal(data, &t); err != nil {
+		return err
+	}
+
+	if t != "FeatureCollection" {
+		return fmt.Errorf("type property not FeatureCollection, got %v", t)
+	}
+
+	return nil
+}
+
+// sequenceReader turns the record separators of a GeoJSON Text Sequence into
+// white space so the texts can be read as consecutive JSON values.
+type sequenceReader struct {
+	r io.Reader
+}
+
+func (s *sequenceReader) Read(p []byte) (int, error) {
+	n, err := s.r.Read(p)
+	for i, b := range p[:n] {
+		if b == recordSeparator {
+			p[i] = '\n'
+		}
+	}
+
+	return n, err
+}
+
+// StreamFormat is the layout of the stream written by an Encoder.
+type StreamFormat int
+
+const (
+	// StreamFeatureCollection writes a single feature collection holding every feature.
+	StreamFeatureCollection StreamFormat = iota
+
+	// StreamNewlineDelimited writes every feature on its own line.
+	StreamNewlineDelimited
+
+	// StreamTextSequence writes an RFC 8142 GeoJSON Text Sequence, every feature
+	// is preceded by a record separator and followed by a line feed.
+	StreamTextSequence
+)
+
+// An Encoder writes features one at a time to a stream of RFC 7946 GeoJSON.
+// Close must be called once every feature was written to complete a feature collection.
+type Encoder struct {
+	w      io.Writer
+	format StreamFormat
+	count  int
+	closed bool
+}
+
+// NewEncoder returns an encoder writing to w in the given format.
+func NewEncoder(w io.Writer, format StreamFormat) *Encoder {
+	return &Encoder{w: w, format: format}
+}
+
+// Encode writes the feature to the stream.
+func (e *Encoder) Encode(f *Feature) error {
+	if f == nil {
+		return errors.New("not a valid feature, got <nil>")
+	}
+
+	return e.encode(f)
+}
+
+// EncodeGeometry writes the geometry to the stream, it is wrapped in a feature
+// without properties when writing a feature collection.
+func (e *Encoder) EncodeGeometry(g *Geometry) error {
+	if g == nil {
+		return errors.New("not a valid geometry, got <nil>")
+	}
+
+	if e.format == StreamFeatureCollection {
+		return e.encode(NewFeature(g))
+	}
+
+	return e.encode(g)
+}
+
+func (e *Encoder) encode(v interface{}) error {
+	if e.closed {
+		return errors.New("encoder already closed")
+	}
+
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	var prefix, suffix string
+	switch e.format {
+	case StreamFeatureCollection:
+		prefix = ","
+		if e.count == 0 {
+			prefix = `{"type":"FeatureCollection","features":[`
+		}
+	case StreamNewlineDelimited:
+		suffix = "\n"
+	case StreamTextSequence:
+		prefix, suffix = string(rune(recordSeparator)), "\n"
+	default:
+		return fmt.Errorf("unknown stream format %d", e.format)
+	}
+
+	if _, err := io.WriteString(e.w, prefix); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(data); err != nil {
+		return err
+	}
+	if _, err := io.WriteString(e.w, suffix); err != nil {
+		return err
+	}
+	e.count++
+
+	return nil
+}
+
+// Close completes the feature collection, it does nothing for the other formats.
+// It does not close the underlying writer.
+func (e *Encoder) Close() error {
+	if e.closed {
+		return nil
+	}
+	e.closed = true
+
+	if e.format != StreamFeatureCollection {
+		return nil
+	}
+
+	end := "]}\n"
+	if e.count == 0 {
+		end = `{"type":"FeatureCollection","features":[]}` + "\n"
+	}
+
+	_, err := io.WriteString(e.w, end)
+	return err
+}
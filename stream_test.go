@@ -0,0 +1,178 @@
+package geojson
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func decodeAll(t *testing.T, d *Decoder) []*Feature {
+	t.Helper()
+
+	var features []*Feature
+	for {
+		f, err := d.Decode()
+		if err == io.EOF {
+			return features
+		}
+		if err != nil {
+			t.Fatalf("should decode the stream just fine but got %v", err)
+		}
+		features = append(features, f)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	cases := []struct {
+		name  string
+		input string
+	}{
+		{
+			name:  "feature collection",
+			input: `{"type":"FeatureCollection","features":[{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}},{"type":"Point","coordinates":[3,4]}]}`,
+		},
+		{
+			name:  "type after features",
+			input: `{"features":[{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}},{"type":"Point","coordinates":[3,4]}],"bbox":[1,2,3,4],"type":"FeatureCollection"}`,
+		},
+		{
+			name: "newline delimited",
+			input: `{"type":"Feature","id":1,"geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}}
+{"type":"Point","coordinates":[3,4]}
+`,
+		},
+		{
+			name:  "text sequence",
+			input: "\x1e{\"type\":\"Feature\",\"id\":1,\"geometry\":{\"type\":\"Point\",\"coordinates\":[1,2]},\"properties\":{\"name\":\"a\"}}\n\x1e{\"type\":\"Point\",\"coordinates\":[3,4]}\n",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			features := decodeAll(t, NewDecoder(strings.NewReader(c.input)))
+			if len(features) != 2 {
+				t.Fatalf("incorrect number of features, got %d", len(features))
+			}
+
+			if features[0].ID != int64(1) || features[0].Properties["name"] != "a" || !reflect.DeepEqual(features[0].Geometry.Point, Point{1, 2}) {
+				t.Errorf("incorrect feature, got %+v", features[0])
+			}
+
+			if !reflect.DeepEqual(features[1].Geometry, NewPoint(Point{3, 4})) {
+				t.Errorf("incorrect geometry, got %+v", features[1].Geometry)
+			}
+		})
+	}
+}
+
+func TestDecoderOptions(t *testing.T) {
+	input := `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Circle","radius":10},"properties":{}},{"type":"Feature","geometry":{"type":"point","coordinates":[1,2]},"properties":{}}]}
+{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2,3,4]},"properties":{}}`
+
+	if _, err := NewDecoder(strings.NewReader(input)).Decode(); err == nil {
+		t.Errorf("should fail to decode an unknown geometry type without options")
+	}
+
+	d := NewDecoder(strings.NewReader(input))
+	d.Opts = DecodeOptions{Lenient: true, StrictPositions: true}
+
+	f, err := d.Decode()
+	if err != nil {
+		t.Fatalf("should decode the unknown geometry type just fine but got %v", err)
+	}
+	if f.Geometry.Type != "Circle" || f.Geometry.Raw.Lookup("radius").Int64() != 10 {
+		t.Errorf("unknown geometry should be kept as raw bson, got %+v", f.Geometry)
+	}
+
+	if f, err = d.Decode(); err != nil || !f.Geometry.IsPoint() {
+		t.Errorf("should decode the lowercase geometry type just fine, got %+v, %v", f, err)
+	}
+
+	if _, err = d.Decode(); err == nil {
+		t.Errorf("should fail to decode a position of 4 elements with StrictPositions")
+	}
+}
+
+func TestDecoderInvalid(t *testing.T) {
+	cases := []string{
+		`[1, 2]`,
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":"a"}}`,
+		`{"type":"Topology","features":[]}`,
+		`{"features":[]}`,
+		`{"type":"FeatureCollection","features":[null]}`,
+		`{"type":"FeatureCollection","features":{}}`,
+		`{"type":"FeatureCollection"}`,
+		`{"type":"Point","coordinates":[1,2]`,
+	}
+
+	for _, input := range cases {
+		d := NewDecoder(strings.NewReader(input))
+		var err error
+		for err == nil {
+			_, err = d.Decode()
+		}
+
+		if err == io.EOF {
+			t.Errorf("should fail on %s", input)
+		}
+	}
+}
+
+func TestEncoder(t *testing.T) {
+	f := NewPointFeature(Point{1, 2})
+	f.SetProperty("name", "a")
+	g := NewLineString([]Point{{1, 2}, {3, 4}})
+
+	cases := []struct {
+		format StreamFormat
+		want   string
+	}{
+		{
+			format: StreamFeatureCollection,
+			want:   `{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}},{"type":"Feature","geometry":{"type":"LineString","coordinates":[[1,2],[3,4]]},"properties":{}}]}` + "\n",
+		},
+		{
+			format: StreamNewlineDelimited,
+			want:   `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}}` + "\n" + `{"type":"LineString","coordinates":[[1,2],[3,4]]}` + "\n",
+		},
+		{
+			format: StreamTextSequence,
+			want:   "\x1e" + `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2]},"properties":{"name":"a"}}` + "\n\x1e" + `{"type":"LineString","coordinates":[[1,2],[3,4]]}` + "\n",
+		},
+	}
+
+	for _, c := range cases {
+		var buf bytes.Buffer
+		e := NewEncoder(&buf, c.format)
+		if err := e.Encode(f); err != nil {
+			t.Fatalf("should encode feature just fine but got %v", err)
+		}
+		if err := e.EncodeGeometry(g); err != nil {
+			t.Fatalf("should encode geometry just fine but got %v", err)
+		}
+		if err := e.Close(); err != nil {
+			t.Fatalf("should close just fine but got %v", err)
+		}
+
+		if buf.String() != c.want {
+			t.Errorf("incorrect stream, got %s", buf.String())
+		}
+
+		features := decodeAll(t, NewDecoder(&buf))
+		if len(features) != 2 || !reflect.DeepEqual(features[1].Geometry, g) {
+			t.Errorf("incorrect round trip, got %+v", features)
+		}
+	}
+
+	var buf bytes.Buffer
+	e := NewEncoder(&buf, StreamFeatureCollection)
+	if err := e.Close(); err != nil || buf.String() != `{"type":"FeatureCollection","features":[]}`+"\n" {
+		t.Errorf("incorrect empty collection, got %s, %v", buf.String(), err)
+	}
+
+	if err := e.Encode(f); err == nil {
+		t.Errorf("should fail to encode after close")
+	}
+}
@@ -0,0 +1,374 @@
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// The validation failures reported by Validate, they follow the rules applied
+// by a MongoDB 2dsphere index.
+// https://docs.mongodb.com/v4.2/reference/geojson/
+var (
+	ErrUnknownType          = errors.New("unknown geometry type")
+	ErrNilGeometry          = errors.New("geometry is nil")
+	ErrInvalidPosition      = errors.New("position must have at least 2 elements")
+	ErrLongitudeOutOfRange  = errors.New("longitude must be between -180 and 180")
+	ErrLatitudeOutOfRange   = errors.New("latitude must be between -90 and 90")
+	ErrTooFewPositions      = errors.New("too few positions")
+	ErrRingNotClosed        = errors.New("ring is not closed")
+	ErrRingSelfIntersection = errors.New("ring is self-intersecting")
+	ErrEmptyPolygon         = errors.New("polygon has no exterior ring")
+	ErrHoleOutsideShell     = errors.New("hole is not contained in the exterior ring")
+	ErrHolesOverlap         = errors.New("hole overlaps another hole")
+)
+
+// ValidationError describes why a geometry would be rejected by a 2dsphere index.
+// Path is the coordinate path of the offending element, for example polygon[0][3],
+// Err is one of the Err* values of this package and can be tested with errors.Is.
+type ValidationError struct {
+	Path string
+	Err  error
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Path, e.Err)
+}
+
+// Unwrap returns the underlying validation failure.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+func validationError(err error, format string, args ...interface{}) error {
+	return &ValidationError{Path: fmt.Sprintf(format, args...), Err: err}
+}
+
+// Validate checks the geometry against the MongoDB 2dsphere rules:
+// positions have a longitude in [-180, 180] and a latitude in [-90, 90],
+// lines have at least 2 positions, rings have at least 4 positions, are closed
+// and do not self-intersect, holes lie inside their exterior ring and neither
+// cross, contain nor share an edge with each other.
+// Consecutive duplicate positions of a ring are collapsed, as by a 2dsphere index.
+// The first failure found is returned as a *ValidationError.
+// The holes are checked on the sphere with great circle edges, as by a 2dsphere
+// index, so polygons crossing the antimeridian are supported. The self-intersection
+// of a ring is checked in the lon/lat plane, the result may differ from the index
+// for rings with long edges or near the poles.
+func (g *Geometry) Validate() error {
+	return validateGeometry(g, "")
+}
+
+func validateGeometry(g *Geometry, prefix string) error {
+	if g == nil {
+		return validationError(ErrNilGeometry, "%sgeometry", prefix)
+	}
+
+	switch g.Type {
+	case GeometryPoint:
+		return validatePosition(g.Point, "%spoint", prefix)
+	case GeometryMultiPoint:
+		for i, p := range g.MultiPoint {
+			if err := validatePosition(p, "%smultipoint[%d]", prefix, i); err != nil {
+				return err
+			}
+		}
+	case GeometryLineString:
+		return validateLine(g.LineString, prefix+"linestring")
+	case GeometryMultiLineString:
+		for i, line := range g.MultiLineString {
+			if err := validateLine(line, fmt.Sprintf("%smultilinestring[%d]", prefix, i)); err != nil {
+				return err
+			}
+		}
+	case GeometryPolygon:
+		return validatePolygon(g.Polygon, prefix+"polygon", g.CRS.IsStrictWinding())
+	case GeometryMultiPolygon:
+		for i, polygon := range g.MultiPolygon {
+			if err := validatePolygon(polygon, fmt.Sprintf("%smultipolygon[%d]", prefix, i), g.CRS.IsStrictWinding()); err != nil {
+				return err
+			}
+		}
+	case GeometryCollection:
+		for i, child := range g.Geometries {
+			if err := validateGeometry(child, fmt.Sprintf("%sgeometries[%d].", prefix, i)); err != nil {
+				return err
+			}
+		}
+	default:
+		return validationError(ErrUnknownType, "%stype", prefix)
+	}
+
+	return nil
+}
+
+func validatePosition(p Point, format string, args ...interface{}) error {
+	if len(p) < 2 {
+		return validationError(ErrInvalidPosition, format, args...)
+	}
+	if math.IsNaN(p[0]) || p[0] < -180 || p[0] > 180 {
+		return validationError(ErrLongitudeOutOfRange, format, args...)
+	}
+	if math.IsNaN(p[1]) || p[1] < -90 || p[1] > 90 {
+		return validationError(ErrLatitudeOutOfRange, format, args...)
+	}
+
+	return nil
+}
+
+func validateLine(line []Point, path string) error {
+	for i, p := range line {
+		if err := validatePosition(p, "%s[%d]", path, i); err != nil {
+			return err
+		}
+	}
+	if len(line) < 2 {
+		return validationError(ErrTooFewPositions, "%s", path)
+	}
+
+	return nil
+}
+
+// validatePolygon checks the rings of the polygon, with strict its exterior ring
+// encloses the region on its left, see NewBigPolygon.
+func validatePolygon(polygon [][]Point, path string, strict bool) error {
+	if len(polygon) == 0 {
+		return validationError(ErrEmptyPolygon, "%s", path)
+	}
+
+	for i, ring := range polygon {
+		if err := validateRing(ring, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+			return err
+		}
+	}
+
+	rings := make([]*sphereRing, len(polygon))
+	for i, ring := range polygon {
+		rings[i] = newSphereRing(ring, strict && i == 0)
+	}
+
+	for i := 1; i < len(rings); i++ {
+		if j, ok := holeWithin(rings[i], rings[0]); !ok {
+			return validationError(ErrHoleOutsideShell, "%s[%d][%d]", path, i, j)
+		}
+
+		for k := 1; k < i; k++ {
+			if j, ok := holesDisjoint(rings[i], rings[k]); !ok {
+				return validationError(ErrHolesOverlap, "%s[%d][%d]", path, i, j)
+			}
+		}
+	}
+
+	return nil
+}
+
+// holeWithin reports whether every vertex of the hole lies inside or on the shell
+// and no edges of the two rings cross, otherwise it returns the index of the first
+// offending vertex of the hole.
+func holeWithin(hole, shell *sphereRing) (int, bool) {
+	for i, v := range hole.vertices {
+		if !shell.onBoundary(v) && !shell.contains(v) {
+			return i, false
+		}
+	}
+
+	return ringEdgesApart(hole, shell, false)
+}
+
+// holesDisjoint reports whether the holes a and b neither cross, share an edge
+// nor lie one inside the other, they may touch at their vertices. Otherwise it
+// returns the index of the first offending vertex of a.
+func holesDisjoint(a, b *sphereRing) (int, bool) {
+	if i, ok := ringEdgesApart(a, b, true); !ok {
+		return i, false
+	}
+
+	for i, v := range a.vertices {
+		if !b.onBoundary(v) && b.contains(v) {
+			return i, false
+		}
+	}
+
+	for _, v := range b.vertices {
+		if !a.onBoundary(v) && a.contains(v) {
+			return 0, false
+		}
+	}
+
+	return 0, true
+}
+
+// ringEdgesApart reports whether no edge of a crosses an edge of b, or with shared
+// is the same as an edge of b, otherwise it returns the index of the first vertex
+// of the offending edge of a.
+func ringEdgesApart(a, b *sphereRing, shared bool) (int, bool) {
+	for i, a1 := range a.vertices {
+		a2 := a.vertices[(i+1)%len(a.vertices)]
+		for j, b1 := range b.vertices {
+			b2 := b.vertices[(j+1)%len(b.vertices)]
+			touch := a1.near(b1) || a1.near(b2) || a2.near(b1) || a2.near(b2)
+			if !touch && arcsCross(a1, a2, b1, b2) {
+				return i, false
+			}
+			if shared && !a1.near(a2) && ((a1.near(b1) && a2.near(b2)) || (a1.near(b2) && a2.near(b1))) {
+				return i, false
+			}
+		}
+	}
+
+	return 0, true
+}
+
+func validateRing(ring []Point, path string) error {
+	for i, p := range ring {
+		if err := validatePosition(p, "%s[%d]", path, i); err != nil {
+			return err
+		}
+	}
+	if len(ring) < 4 {
+		return validationError(ErrTooFewPositions, "%s", path)
+	}
+	if !pointEqual(ring[0], ring[len(ring)-1]) {
+		return validationError(ErrRingNotClosed, "%s[%d]", path, len(ring)-1)
+	}
+	if len(ringVertices(ring)) < 4 {
+		return validationError(ErrTooFewPositions, "%s", path)
+	}
+	if i, ok := ringSimple(ring); !ok {
+		return validationError(ErrRingSelfIntersection, "%s[%d]", path, i)
+	}
+
+	return nil
+}
+
+// ringSimple reports whether no two non-adjacent edges of the closed ring meet,
+// otherwise it returns the index of the first vertex of the offending edge.
+// Consecutive duplicate vertices are collapsed first.
+func ringSimple(ring []Point) (int, bool) {
+	vertices := ringVertices(ring)
+	n := len(vertices) - 1 // number of edges
+	for i := 0; i < n; i++ {
+		for j := i + 1; j < n; j++ {
+			if j == i+1 || (i == 0 && j == n-1) {
+				continue
+			}
+			if segmentsIntersect(ring[vertices[i]], ring[vertices[i+1]], ring[vertices[j]], ring[vertices[j+1]]) {
+				return vertices[j], false
+			}
+		}
+	}
+
+	return 0, true
+}
+
+// ringVertices returns the indexes of the positions of the ring that differ from
+// the previous one, a ring A, A, B, C, A has the vertices 0, 2, 3 and 4.
+func ringVertices(ring []Point) []int {
+	vertices := make([]int, 0, len(ring))
+	for i, p := range ring {
+		if i == 0 || !pointEqual(p, ring[i-1]) {
+			vertices = append(vertices, i)
+		}
+	}
+
+	return vertices
+}
+
+// ringWithin reports whether every vertex of inner lies inside or on outer and no
+// edges of the two rings cross, otherwise it returns the index of the first
+// offending vertex of inner.
+func ringWithin(inner, outer []Point) (int, bool) {
+	for i, p := range inner {
+		if ringContains(outer, p) < 0 {
+			return i, false
+		}
+	}
+	for i := 0; i+1 < len(inner); i++ {
+		for j := 0; j+1 < len(outer); j++ {
+			if segmentsCross(inner[i], inner[i+1], outer[j], outer[j+1]) {
+				return i, false
+			}
+		}
+	}
+
+	return 0, true
+}
+
+func pointEqual(a, b Point) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return true
+}
+
+// cross returns the z component of (b - a) x (c - a), positive when a, b, c turn counter-clockwise.
+func cross(a, b, c Point) float64 {
+	return (b[0]-a[0])*(c[1]-a[1]) - (b[1]-a[1])*(c[0]-a[0])
+}
+
+func sign(f float64) int {
+	switch {
+	case f > 0:
+		return 1
+	case f < 0:
+		return -1
+	}
+	return 0
+}
+
+// onSegment reports whether p, known to be collinear with a and b, lies on segment ab.
+func onSegment(a, b, p Point) bool {
+	return math.Min(a[0], b[0]) <= p[0] && p[0] <= math.Max(a[0], b[0]) &&
+		math.Min(a[1], b[1]) <= p[1] && p[1] <= math.Max(a[1], b[1])
+}
+
+// segmentsIntersect reports whether the segments p1p2 and q1q2 share at least one point.
+func segmentsIntersect(p1, p2, q1, q2 Point) bool {
+	d1 := sign(cross(q1, q2, p1))
+	d2 := sign(cross(q1, q2, p2))
+	d3 := sign(cross(p1, p2, q1))
+	d4 := sign(cross(p1, p2, q2))
+
+	if d1*d2 < 0 && d3*d4 < 0 {
+		return true
+	}
+
+	return (d1 == 0 && onSegment(q1, q2, p1)) ||
+		(d2 == 0 && onSegment(q1, q2, p2)) ||
+		(d3 == 0 && onSegment(p1, p2, q1)) ||
+		(d4 == 0 && onSegment(p1, p2, q2))
+}
+
+// segmentsCross reports whether the segments p1p2 and q1q2 properly cross each other,
+// touching at an end point or overlapping is not a crossing.
+func segmentsCross(p1, p2, q1, q2 Point) bool {
+	return sign(cross(q1, q2, p1))*sign(cross(q1, q2, p2)) < 0 &&
+		sign(cross(p1, p2, q1))*sign(cross(p1, p2, q2)) < 0
+}
+
+// ringContains locates p relative to the closed ring in the lon/lat plane,
+// it returns 1 when p is inside, 0 when p is on the boundary and -1 when p is outside.
+func ringContains(ring []Point, p Point) int {
+	inside := false
+	for i := 0; i+1 < len(ring); i++ {
+		a, b := ring[i], ring[i+1]
+		if cross(a, b, p) == 0 && onSegment(a, b, p) {
+			return 0
+		}
+		if (a[1] > p[1]) != (b[1] > p[1]) &&
+			p[0] < (b[0]-a[0])*(p[1]-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+	}
+	if inside {
+		return 1
+	}
+
+	return -1
+}
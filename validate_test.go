@@ -0,0 +1,72 @@
+package geojson
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestGeometryValidate(t *testing.T) {
+	square := []Point{{0, 0}, {10, 0}, {10, 10}, {0, 10}, {0, 0}}
+	hole := []Point{{2, 2}, {2, 4}, {4, 4}, {4, 2}, {2, 2}}
+
+	cases := []struct {
+		name string
+		g    *Geometry
+		err  error
+		path string
+	}{
+		{"point", NewPoint(Point{1, 2}), nil, ""},
+		{"point short", NewPoint(Point{1}), ErrInvalidPosition, "point"},
+		{"point latitude", NewPoint(Point{1, 95}), ErrLatitudeOutOfRange, "point"},
+		{"multipoint longitude", NewMultiPoint(Point{1, 2}, Point{181, 2}), ErrLongitudeOutOfRange, "multipoint[1]"},
+		{"linestring", NewLineString([]Point{{1, 2}, {3, 4}}), nil, ""},
+		{"linestring short", NewLineString([]Point{{1, 2}}), ErrTooFewPositions, "linestring"},
+		{"multilinestring", NewMultiLineString([]Point{{1, 2}, {3, 4}}, []Point{{1, 2}}), ErrTooFewPositions, "multilinestring[1]"},
+		{"polygon", NewPolygon([][]Point{square, hole}), nil, ""},
+		{"polygon empty", NewPolygon(nil), ErrEmptyPolygon, "polygon"},
+		{"polygon short ring", NewPolygon([][]Point{{{0, 0}, {1, 1}, {0, 0}}}), ErrTooFewPositions, "polygon[0]"},
+		{"polygon unclosed", NewPolygon([][]Point{{{0, 0}, {1, 0}, {1, 1}, {0, 1}}}), ErrRingNotClosed, "polygon[0][3]"},
+		{"polygon bowtie", NewPolygon([][]Point{{{0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}), ErrRingSelfIntersection, "polygon[0][2]"},
+		{"polygon duplicate vertices", NewPolygon([][]Point{{{0, 0}, {0, 0}, {10, 0}, {10, 10}, {10, 10}, {0, 0}}}), nil, ""},
+		{"polygon duplicate vertices bowtie", NewPolygon([][]Point{{{0, 0}, {0, 0}, {10, 10}, {10, 0}, {0, 10}, {0, 0}}}), ErrRingSelfIntersection, "polygon[0][3]"},
+		{"polygon degenerate", NewPolygon([][]Point{{{0, 0}, {0, 0}, {10, 0}, {0, 0}}}), ErrTooFewPositions, "polygon[0]"},
+		{"polygon hole outside", NewPolygon([][]Point{square, {{2, 2}, {2, 4}, {14, 4}, {14, 2}, {2, 2}}}), ErrHoleOutsideShell, "polygon[1][2]"},
+		{"polygon across the antimeridian", NewPolygon([][]Point{
+			{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+			{{175, -5}, {179, -5}, {179, 5}, {175, 5}, {175, -5}},
+		}), nil, ""},
+		{"polygon hole outside across the antimeridian", NewPolygon([][]Point{
+			{{170, -10}, {-170, -10}, {-170, 10}, {170, 10}, {170, -10}},
+			{{0, -5}, {5, -5}, {5, 5}, {0, 5}, {0, -5}},
+		}), ErrHoleOutsideShell, "polygon[1][0]"},
+		{"polygon holes touching", NewPolygon([][]Point{square, hole, {{4, 4}, {4, 6}, {6, 6}, {6, 4}, {4, 4}}}), nil, ""},
+		{"polygon holes overlapping", NewPolygon([][]Point{square, hole, {{3, 3}, {3, 6}, {6, 6}, {6, 3}, {3, 3}}}), ErrHolesOverlap, "polygon[2][0]"},
+		{"polygon hole twice", NewPolygon([][]Point{square, hole, hole}), ErrHolesOverlap, "polygon[2][0]"},
+		{"polygon holes sharing an edge", NewPolygon([][]Point{square, hole, {{4, 2}, {6, 2}, {6, 4}, {4, 4}, {4, 2}}}), ErrHolesOverlap, "polygon[2][3]"},
+		{"polygon hole inside a hole", NewPolygon([][]Point{square, {{1, 1}, {1, 8}, {8, 8}, {8, 1}, {1, 1}}, hole}), ErrHolesOverlap, "polygon[2][0]"},
+		{"multipolygon", NewMultiPolygon([][]Point{square}, [][]Point{{{0, 0}, {0, 95}, {1, 1}, {0, 0}}}), ErrLatitudeOutOfRange, "multipolygon[1][0][1]"},
+		{"collection", NewGeometryCollection(NewPoint(Point{1, 2}), NewLineString([]Point{{1, 2}})), ErrTooFewPositions, "geometries[1].linestring"},
+		{"collection nil", NewGeometryCollection(nil), ErrNilGeometry, "geometries[0].geometry"},
+		{"unknown", &Geometry{Type: "Circle"}, ErrUnknownType, "type"},
+	}
+
+	for _, c := range cases {
+		err := c.g.Validate()
+		if c.err == nil {
+			if err != nil {
+				t.Errorf("%s: should be valid but got %v", c.name, err)
+			}
+			continue
+		}
+
+		if !errors.Is(err, c.err) {
+			t.Errorf("%s: expected %v but got %v", c.name, c.err, err)
+			continue
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) || verr.Path != c.path {
+			t.Errorf("%s: expected path %q but got %v", c.name, c.path, err)
+		}
+	}
+}
@@ -0,0 +1,539 @@
+package geojson
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// SRIDWGS84 is the spatial reference identifier of WGS84, the only coordinate
+// reference system supported by MongoDB and the one written by Value.
+const SRIDWGS84 = 4326
+
+// WKB geometry type codes
+const (
+	wkbPoint              uint32 = 1
+	wkbLineString         uint32 = 2
+	wkbPolygon            uint32 = 3
+	wkbMultiPoint         uint32 = 4
+	wkbMultiLineString    uint32 = 5
+	wkbMultiPolygon       uint32 = 6
+	wkbGeometryCollection uint32 = 7
+)
+
+// EWKB flags, as used by PostGIS
+const (
+	ewkbZ    uint32 = 0x80000000
+	ewkbM    uint32 = 0x40000000
+	ewkbSRID uint32 = 0x20000000
+)
+
+var wkbCodes = map[GeometryType]uint32{
+	GeometryPoint:           wkbPoint,
+	GeometryLineString:      wkbLineString,
+	GeometryPolygon:         wkbPolygon,
+	GeometryMultiPoint:      wkbMultiPoint,
+	GeometryMultiLineString: wkbMultiLineString,
+	GeometryMultiPolygon:    wkbMultiPolygon,
+	GeometryCollection:      wkbGeometryCollection,
+}
+
+// MarshalWKB encodes the geometry as ISO Well-Known Binary with the given byte order,
+// Z, M and ZM dimensions are detected the same way as MarshalWKT does.
+func (g *Geometry) MarshalWKB(order binary.ByteOrder) ([]byte, error) {
+	w := &wkbWriter{order: order}
+	if err := w.geometry(g, 0, false, wktXY); err != nil {
+		return nil, err
+	}
+
+	return w.buf, nil
+}
+
+// MarshalEWKB encodes the geometry as PostGIS Extended Well-Known Binary
+// with the given SRID and byte order.
+func (g *Geometry) MarshalEWKB(srid uint32, order binary.ByteOrder) ([]byte, error) {
+	w := &wkbWriter{order: order}
+	if err := w.geometry(g, srid, true, wktXY); err != nil {
+		return nil, err
+	}
+
+	return w.buf, nil
+}
+
+// UnmarshalWKB decodes ISO WKB or PostGIS EWKB, in either byte order, into a geometry.
+// The SRID is 0 unless the data is EWKB carrying one.
+func UnmarshalWKB(data []byte) (*Geometry, uint32, error) {
+	r := &wkbReader{data: data}
+	g, srid, err := r.geometry()
+	if err != nil {
+		return nil, 0, err
+	}
+	if r.pos != len(data) {
+		return nil, 0, fmt.Errorf("wkb: %d unexpected trailing bytes", len(data)-r.pos)
+	}
+
+	return g, srid, nil
+}
+
+// UnmarshalWKBHex decodes hex encoded WKB or EWKB, as printed by PostGIS, into a geometry.
+func UnmarshalWKBHex(s string) (*Geometry, uint32, error) {
+	data, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, 0, fmt.Errorf("wkb: %w", err)
+	}
+
+	return UnmarshalWKB(data)
+}
+
+// Scan decodes a WKB, EWKB or hex encoded (E)WKB column value into the geometry.
+// This fulfills the sql.Scanner interface.
+func (g *Geometry) Scan(src interface{}) error {
+	var (
+		decoded *Geometry
+		err     error
+	)
+
+	switch v := src.(type) {
+	case nil:
+		*g = Geometry{}
+		return nil
+	case []byte:
+		if isHexWKB(v) {
+			decoded, _, err = UnmarshalWKBHex(string(v))
+		} else {
+			decoded, _, err = UnmarshalWKB(v)
+		}
+	case string:
+		decoded, _, err = UnmarshalWKBHex(v)
+	default:
+		return fmt.Errorf("cannot scan %T into a geometry", src)
+	}
+	if err != nil {
+		return err
+	}
+
+	*g = *decoded
+	return nil
+}
+
+// isHexWKB reports whether data is hex text rather than binary WKB,
+// binary WKB always starts with a 0 or 1 byte order marker.
+func isHexWKB(data []byte) bool {
+	return len(data) >= 2 && data[0] == '0' && (data[1] == '0' || data[1] == '1')
+}
+
+// Value encodes the geometry as hex EWKB with the WGS84 SRID, the text form accepted
+// by PostGIS geometry columns.
+// This fulfills the driver.Valuer interface.
+// nolint: gocritic
+func (g Geometry) Value() (driver.Value, error) {
+	data, err := g.MarshalEWKB(SRIDWGS84, binary.LittleEndian)
+	if err != nil {
+		return nil, err
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+type wkbWriter struct {
+	buf   []byte
+	order binary.ByteOrder
+	tmp   [8]byte
+}
+
+func (w *wkbWriter) uint32(v uint32) {
+	w.order.PutUint32(w.tmp[:4], v)
+	w.buf = append(w.buf, w.tmp[:4]...)
+}
+
+func (w *wkbWriter) float64(f float64) {
+	w.order.PutUint64(w.tmp[:], math.Float64bits(f))
+	w.buf = append(w.buf, w.tmp[:]...)
+}
+
+func (w *wkbWriter) header(code uint32, dim wktDimension, srid uint32, extended bool) {
+	if w.order == binary.LittleEndian {
+		w.buf = append(w.buf, 1)
+	} else {
+		w.buf = append(w.buf, 0)
+	}
+
+	if !extended {
+		switch dim {
+		case wktXYZ:
+			code += 1000
+		case wktXYM:
+			code += 2000
+		case wktXYZM:
+			code += 3000
+		}
+		w.uint32(code)
+		return
+	}
+
+	switch dim {
+	case wktXYZ:
+		code |= ewkbZ
+	case wktXYM:
+		code |= ewkbM
+	case wktXYZM:
+		code |= ewkbZ | ewkbM
+	}
+	if srid != 0 {
+		code |= ewkbSRID
+	}
+	w.uint32(code)
+	if srid != 0 {
+		w.uint32(srid)
+	}
+}
+
+func (w *wkbWriter) position(p Point, dim wktDimension) error {
+	if positionDimension(p) != dim {
+		return fmt.Errorf("wkb: mixed coordinate dimensions, got %v", p)
+	}
+
+	w.float64(p[0])
+	w.float64(p[1])
+	switch dim {
+	case wktXYZ:
+		w.float64(p[2])
+	case wktXYM:
+		w.float64(p[3])
+	case wktXYZM:
+		w.float64(p[2])
+		w.float64(p[3])
+	}
+
+	return nil
+}
+
+func (w *wkbWriter) positions(points []Point, dim wktDimension) error {
+	w.uint32(uint32(len(points)))
+	for _, p := range points {
+		if err := w.position(p, dim); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (w *wkbWriter) paths(paths [][]Point, dim wktDimension) error {
+	w.uint32(uint32(len(paths)))
+	for _, path := range paths {
+		if err := w.positions(path, dim); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// geometry writes g, only the outermost geometry of EWKB carries the SRID.
+// A geometry without positions, e.g. an empty member, takes the dimension parent.
+func (w *wkbWriter) geometry(g *Geometry, srid uint32, extended bool, parent wktDimension) error {
+	if g == nil {
+		return errors.New("wkb: nil geometry")
+	}
+
+	code, ok := wkbCodes[g.Type]
+	if !ok {
+		return fmt.Errorf("wkb: unknown geometry type %q", g.Type)
+	}
+
+	dim, ok := geometryDimension(g)
+	if !ok {
+		dim = parent
+	}
+	w.header(code, dim, srid, extended)
+
+	switch g.Type {
+	case GeometryPoint:
+		if len(g.Point) == 0 {
+			// an empty point is written with NaN coordinates
+			for i := 0; i < dim.size(); i++ {
+				w.float64(math.NaN())
+			}
+			return nil
+		}
+		return w.position(g.Point, dim)
+	case GeometryMultiPoint:
+		w.uint32(uint32(len(g.MultiPoint)))
+		for _, p := range g.MultiPoint {
+			if err := w.geometry(NewPoint(p), 0, extended, dim); err != nil {
+				return err
+			}
+		}
+	case GeometryLineString:
+		return w.positions(g.LineString, dim)
+	case GeometryMultiLineString:
+		w.uint32(uint32(len(g.MultiLineString)))
+		for _, line := range g.MultiLineString {
+			if err := w.geometry(NewLineString(line), 0, extended, dim); err != nil {
+				return err
+			}
+		}
+	case GeometryPolygon:
+		return w.paths(g.Polygon, dim)
+	case GeometryMultiPolygon:
+		w.uint32(uint32(len(g.MultiPolygon)))
+		for _, polygon := range g.MultiPolygon {
+			if err := w.geometry(NewPolygon(polygon), 0, extended, dim); err != nil {
+				return err
+			}
+		}
+	case GeometryCollection:
+		w.uint32(uint32(len(g.Geometries)))
+		for _, child := range g.Geometries {
+			if err := w.geometry(child, 0, extended, dim); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+type wkbReader struct {
+	data  []byte
+	pos   int
+	order binary.ByteOrder
+}
+
+var errWKBTooShort = errors.New("wkb: unexpected end of data")
+
+func (r *wkbReader) uint32() (uint32, error) {
+	if len(r.data)-r.pos < 4 {
+		return 0, errWKBTooShort
+	}
+	v := r.order.Uint32(r.data[r.pos:])
+	r.pos += 4
+
+	return v, nil
+}
+
+func (r *wkbReader) float64() (float64, error) {
+	if len(r.data)-r.pos < 8 {
+		return 0, errWKBTooShort
+	}
+	v := math.Float64frombits(r.order.Uint64(r.data[r.pos:]))
+	r.pos += 8
+
+	return v, nil
+}
+
+// count reads an element count and checks the remaining data can hold it.
+func (r *wkbReader) count(minSize int) (int, error) {
+	n, err := r.uint32()
+	if err != nil {
+		return 0, err
+	}
+	if uint64(n)*uint64(minSize) > uint64(len(r.data)-r.pos) {
+		return 0, errWKBTooShort
+	}
+
+	return int(n), nil
+}
+
+func (r *wkbReader) position(dim wktDimension) (Point, error) {
+	values := make(Point, dim.size())
+	for i := range values {
+		f, err := r.float64()
+		if err != nil {
+			return nil, err
+		}
+		values[i] = f
+	}
+	if dim == wktXYM {
+		return Point{values[0], values[1], math.NaN(), values[2]}, nil
+	}
+
+	return values, nil
+}
+
+func (r *wkbReader) positions(dim wktDimension) ([]Point, error) {
+	n, err := r.count(8 * dim.size())
+	if err != nil {
+		return nil, err
+	}
+
+	points := make([]Point, 0, n)
+	for i := 0; i < n; i++ {
+		p, err := r.position(dim)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, p)
+	}
+
+	return points, nil
+}
+
+func (r *wkbReader) paths(dim wktDimension) ([][]Point, error) {
+	n, err := r.count(4)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := make([][]Point, 0, n)
+	for i := 0; i < n; i++ {
+		path, err := r.positions(dim)
+		if err != nil {
+			return nil, err
+		}
+		paths = append(paths, path)
+	}
+
+	return paths, nil
+}
+
+// header reads the byte order, type code, dimension and optional EWKB SRID of a geometry.
+func (r *wkbReader) header() (uint32, wktDimension, uint32, error) {
+	if r.pos >= len(r.data) {
+		return 0, wktXY, 0, errWKBTooShort
+	}
+	switch r.data[r.pos] {
+	case 0:
+		r.order = binary.BigEndian
+	case 1:
+		r.order = binary.LittleEndian
+	default:
+		return 0, wktXY, 0, fmt.Errorf("wkb: invalid byte order %d", r.data[r.pos])
+	}
+	r.pos++
+
+	code, err := r.uint32()
+	if err != nil {
+		return 0, wktXY, 0, err
+	}
+
+	var srid uint32
+	hasZ, hasM := code&ewkbZ != 0, code&ewkbM != 0
+	if code&ewkbSRID != 0 {
+		if srid, err = r.uint32(); err != nil {
+			return 0, wktXY, 0, err
+		}
+	}
+	code &^= ewkbZ | ewkbM | ewkbSRID
+
+	switch code / 1000 {
+	case 1:
+		hasZ = true
+	case 2:
+		hasM = true
+	case 3:
+		hasZ, hasM = true, true
+	}
+	code %= 1000
+
+	dim := wktXY
+	switch {
+	case hasZ && hasM:
+		dim = wktXYZM
+	case hasZ:
+		dim = wktXYZ
+	case hasM:
+		dim = wktXYM
+	}
+
+	return code, dim, srid, nil
+}
+
+// child reads a member of a multi geometry and checks its type.
+func (r *wkbReader) child(code uint32) (*Geometry, error) {
+	g, _, err := r.geometry()
+	if err != nil {
+		return nil, err
+	}
+	if wkbCodes[g.Type] != code {
+		return nil, fmt.Errorf("wkb: unexpected %s member", g.Type)
+	}
+
+	return g, nil
+}
+
+func (r *wkbReader) geometry() (*Geometry, uint32, error) {
+	code, dim, srid, err := r.header()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	g := &Geometry{}
+	switch code {
+	case wkbPoint:
+		g.Type = GeometryPoint
+		var p Point
+		if p, err = r.position(dim); err == nil && !(math.IsNaN(p[0]) && math.IsNaN(p[1])) {
+			g.Point = p
+		}
+	case wkbLineString:
+		g.Type = GeometryLineString
+		g.LineString, err = r.positions(dim)
+	case wkbPolygon:
+		g.Type = GeometryPolygon
+		g.Polygon, err = r.paths(dim)
+	case wkbMultiPoint:
+		g.Type = GeometryMultiPoint
+		err = r.members(func() error {
+			child, err := r.child(wkbPoint)
+			if err == nil {
+				g.MultiPoint = append(g.MultiPoint, child.Point)
+			}
+			return err
+		})
+	case wkbMultiLineString:
+		g.Type = GeometryMultiLineString
+		err = r.members(func() error {
+			child, err := r.child(wkbLineString)
+			if err == nil {
+				g.MultiLineString = append(g.MultiLineString, child.LineString)
+			}
+			return err
+		})
+	case wkbMultiPolygon:
+		g.Type = GeometryMultiPolygon
+		err = r.members(func() error {
+			child, err := r.child(wkbPolygon)
+			if err == nil {
+				g.MultiPolygon = append(g.MultiPolygon, child.Polygon)
+			}
+			return err
+		})
+	case wkbGeometryCollection:
+		g.Type = GeometryCollection
+		err = r.members(func() error {
+			child, _, err := r.geometry()
+			if err == nil {
+				g.Geometries = append(g.Geometries, child)
+			}
+			return err
+		})
+	default:
+		return nil, 0, fmt.Errorf("wkb: unknown geometry type %d", code)
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return g, srid, nil
+}
+
+// members reads the member count of a multi geometry and calls read for each member.
+func (r *wkbReader) members(read func() error) error {
+	// every member has at least a byte order and a type code
+	n, err := r.count(5)
+	if err != nil {
+		return err
+	}
+	for i := 0; i < n; i++ {
+		if err := read(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
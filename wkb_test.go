@@ -0,0 +1,124 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestWKBRoundTrip(t *testing.T) {
+	geometries := []*Geometry{
+		NewPoint(Point{1, 2}),
+		NewPoint(Point{1, 2, 3}),
+		NewPoint(Point{1, 2, math.NaN(), 4}),
+		NewPoint(Point{1, 2, 3, 4}),
+		{Type: GeometryPoint},
+		NewMultiPoint(Point{1, 2}, Point{3, 4}),
+		NewLineString([]Point{{1, 2}, {3, 4}}),
+		NewMultiLineString([]Point{{1, 2}, {3, 4}}, []Point{{5, 6}, {7, 8}}),
+		NewPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}}),
+		NewMultiPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}}, [][]Point{{{1, 1}, {2, 2}, {3, 1}, {1, 1}}}),
+		NewGeometryCollection(NewPoint(Point{1, 2}), NewLineString([]Point{{1, 2}, {3, 4}})),
+		NewGeometryCollection(NewPoint(Point{1, 2, 3})),
+		NewGeometryCollection(&Geometry{Type: GeometryPoint}, NewPoint(Point{1, 2, math.NaN(), 4})),
+	}
+
+	for _, g := range geometries {
+		want, _ := g.MarshalWKT()
+		for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+			data, err := g.MarshalWKB(order)
+			if err != nil {
+				t.Fatalf("should marshal %s to wkb just fine but got %v", want, err)
+			}
+
+			decoded, srid, err := UnmarshalWKB(data)
+			if err != nil {
+				t.Fatalf("should unmarshal %s without issue, err %v", want, err)
+			}
+
+			if got, _ := decoded.MarshalWKT(); got != want || srid != 0 {
+				t.Errorf("wkb should round trip, got %s srid %d, want %s", got, srid, want)
+			}
+
+			data, err = g.MarshalEWKB(SRIDWGS84, order)
+			if err != nil {
+				t.Fatalf("should marshal %s to ewkb just fine but got %v", want, err)
+			}
+
+			decoded, srid, err = UnmarshalWKB(data)
+			if err != nil {
+				t.Fatalf("should unmarshal %s without issue, err %v", want, err)
+			}
+
+			if got, _ := decoded.MarshalWKT(); got != want || srid != SRIDWGS84 {
+				t.Errorf("ewkb should round trip, got %s srid %d, want %s", got, srid, want)
+			}
+		}
+	}
+
+	for want, g := range map[uint32]*Geometry{
+		1007: NewGeometryCollection(NewPoint(Point{1, 2, 3})),
+		2007: NewGeometryCollection(NewPoint(Point{1, 2, math.NaN(), 4})),
+		3007: NewGeometryCollection(NewGeometryCollection(), NewPoint(Point{1, 2, 3, 4})),
+	} {
+		data, err := g.MarshalWKB(binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("should marshal collection %d just fine but got %v", want, err)
+		}
+
+		if code := binary.LittleEndian.Uint32(data[1:5]); code != want {
+			t.Errorf("incorrect collection type code, got %d, want %d", code, want)
+		}
+	}
+}
+
+func TestWKBPostGIS(t *testing.T) {
+	// SELECT ST_AsEWKB('SRID=4326;POINT(1 2)')
+	const ewkb = "0101000020E6100000000000000000F03F0000000000000040"
+
+	g, srid, err := UnmarshalWKBHex(ewkb)
+	if err != nil {
+		t.Fatalf("should unmarshal ewkb without issue, err %v", err)
+	}
+
+	if !g.IsPoint() || g.Point[0] != 1 || g.Point[1] != 2 || srid != SRIDWGS84 {
+		t.Errorf("incorrect geometry, got %+v srid %d", g, srid)
+	}
+
+	value, err := NewPoint(Point{1, 2}).Value()
+	if err != nil {
+		t.Fatalf("should encode value just fine but got %v", err)
+	}
+
+	if !strings.EqualFold(value.(string), ewkb) {
+		t.Errorf("incorrect value, got %v", value)
+	}
+}
+
+func TestGeometryScan(t *testing.T) {
+	wkb, _ := NewLineString([]Point{{1, 2}, {3, 4}}).MarshalWKB(binary.BigEndian)
+
+	for _, src := range []interface{}{wkb, hex.EncodeToString(wkb), []byte(hex.EncodeToString(wkb))} {
+		g := &Geometry{}
+		if err := g.Scan(src); err != nil {
+			t.Fatalf("should scan %T without issue, err %v", src, err)
+		}
+
+		if !g.IsLineString() || len(g.LineString) != 2 {
+			t.Errorf("incorrect geometry, got %+v", g)
+		}
+	}
+
+	g := NewPoint(Point{1, 2})
+	if err := g.Scan(nil); err != nil || g.Type != "" {
+		t.Errorf("scanning NULL should reset the geometry, got %+v, %v", g, err)
+	}
+
+	for _, src := range []interface{}{42, []byte{1, 1, 0}, "zz", []byte{2, 1, 0, 0, 0}} {
+		if err := g.Scan(src); err == nil {
+			t.Errorf("should fail to scan %v", src)
+		}
+	}
+}
@@ -0,0 +1,601 @@
+package geojson
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// The WKT tags of the geometry types.
+var wktTags = map[GeometryType]string{
+	GeometryPoint:           "POINT",
+	GeometryMultiPoint:      "MULTIPOINT",
+	GeometryLineString:      "LINESTRING",
+	GeometryMultiLineString: "MULTILINESTRING",
+	GeometryPolygon:         "POLYGON",
+	GeometryMultiPolygon:    "MULTIPOLYGON",
+	GeometryCollection:      "GEOMETRYCOLLECTION",
+}
+
+// The coordinate dimensions of a WKT geometry.
+type wktDimension int
+
+const (
+	wktXY wktDimension = iota
+	wktXYZ
+	wktXYM
+	wktXYZM
+)
+
+func (d wktDimension) tag() string {
+	switch d {
+	case wktXYZ:
+		return " Z"
+	case wktXYM:
+		return " M"
+	case wktXYZM:
+		return " ZM"
+	}
+	return ""
+}
+
+func (d wktDimension) size() int {
+	switch d {
+	case wktXYZ, wktXYM:
+		return 3
+	case wktXYZM:
+		return 4
+	}
+	return 2
+}
+
+// positionDimension tells the dimension of a position, a measure without elevation
+// is kept as Point{x, y, NaN, m}.
+func positionDimension(p Point) wktDimension {
+	switch {
+	case len(p) == 3:
+		return wktXYZ
+	case len(p) >= 4 && math.IsNaN(p[2]):
+		return wktXYM
+	case len(p) >= 4:
+		return wktXYZM
+	}
+	return wktXY
+}
+
+// MarshalWKT encodes the geometry as Well-Known Text, e.g. POINT (40 5).
+// A Point with 3 elements is written with a Z dimension, with 4 elements as ZM,
+// and as M when its third element is NaN, it fails for more elements.
+// A collection is tagged with the dimension of its first member with coordinates.
+// Geometries, rings and lines without coordinates are written as EMPTY.
+func (g *Geometry) MarshalWKT() (string, error) {
+	var b strings.Builder
+	if err := writeWKT(&b, g); err != nil {
+		return "", err
+	}
+
+	return b.String(), nil
+}
+
+func writeWKT(b *strings.Builder, g *Geometry) error {
+	if g == nil {
+		return errors.New("nil geometry")
+	}
+
+	tag, ok := wktTags[g.Type]
+	if !ok {
+		return fmt.Errorf("unknown geometry type %q", g.Type)
+	}
+	b.WriteString(tag)
+
+	if g.Type == GeometryCollection {
+		if len(g.Geometries) == 0 {
+			b.WriteString(" EMPTY")
+			return nil
+		}
+		dim, _ := geometryDimension(g)
+		b.WriteString(dim.tag())
+		b.WriteString(" (")
+		for i, child := range g.Geometries {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if err := writeWKT(b, child); err != nil {
+				return err
+			}
+		}
+		b.WriteString(")")
+		return nil
+	}
+
+	first := firstPosition(g)
+	if first == nil {
+		b.WriteString(" EMPTY")
+		return nil
+	}
+	w := &wktWriter{b: b, dim: positionDimension(first)}
+	b.WriteString(w.dim.tag())
+	b.WriteString(" ")
+
+	switch g.Type {
+	case GeometryPoint:
+		return w.positions([]Point{g.Point})
+	case GeometryMultiPoint:
+		b.WriteString("(")
+		for i, p := range g.MultiPoint {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if len(p) == 0 {
+				b.WriteString("EMPTY")
+				continue
+			}
+			if err := w.positions([]Point{p}); err != nil {
+				return err
+			}
+		}
+		b.WriteString(")")
+	case GeometryLineString:
+		return w.positions(g.LineString)
+	case GeometryMultiLineString:
+		return w.paths(g.MultiLineString)
+	case GeometryPolygon:
+		return w.paths(g.Polygon)
+	case GeometryMultiPolygon:
+		b.WriteString("(")
+		for i, polygon := range g.MultiPolygon {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if err := w.paths(polygon); err != nil {
+				return err
+			}
+		}
+		b.WriteString(")")
+	}
+
+	return nil
+}
+
+// geometryDimension returns the dimension of the first position of g, looking
+// into the members of a collection, ok is false if it has none.
+func geometryDimension(g *Geometry) (dim wktDimension, ok bool) {
+	if g.Type == GeometryCollection {
+		for _, child := range g.Geometries {
+			if child == nil {
+				continue
+			}
+			if dim, ok := geometryDimension(child); ok {
+				return dim, true
+			}
+		}
+		return wktXY, false
+	}
+
+	first := firstPosition(g)
+	if first == nil {
+		return wktXY, false
+	}
+	return positionDimension(first), true
+}
+
+// firstPosition returns the first non empty position of a non collection geometry,
+// nil if it has none, empty members do not set the dimension.
+func firstPosition(g *Geometry) Point {
+	switch g.Type {
+	case GeometryPoint:
+		return firstIn([]Point{g.Point})
+	case GeometryMultiPoint:
+		return firstIn(g.MultiPoint)
+	case GeometryLineString:
+		return firstIn(g.LineString)
+	case GeometryMultiLineString:
+		return firstInPaths(g.MultiLineString)
+	case GeometryPolygon:
+		return firstInPaths(g.Polygon)
+	case GeometryMultiPolygon:
+		for _, polygon := range g.MultiPolygon {
+			if p := firstInPaths(polygon); p != nil {
+				return p
+			}
+		}
+	}
+	return nil
+}
+
+func firstIn(points []Point) Point {
+	for _, p := range points {
+		if len(p) > 0 {
+			return p
+		}
+	}
+	return nil
+}
+
+func firstInPaths(paths [][]Point) Point {
+	for _, path := range paths {
+		if p := firstIn(path); p != nil {
+			return p
+		}
+	}
+	return nil
+}
+
+type wktWriter struct {
+	b   *strings.Builder
+	dim wktDimension
+}
+
+func (w *wktWriter) number(f float64) {
+	w.b.WriteString(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+func (w *wktWriter) position(p Point) error {
+	if len(p) < 2 || len(p) > 4 {
+		return fmt.Errorf("position must have 2 to 4 coordinates, got %v", p)
+	}
+	if positionDimension(p) != w.dim {
+		return fmt.Errorf("mixed coordinate dimensions, got %v", p)
+	}
+
+	w.number(p[0])
+	w.b.WriteString(" ")
+	w.number(p[1])
+	switch w.dim {
+	case wktXYZ:
+		w.b.WriteString(" ")
+		w.number(p[2])
+	case wktXYM:
+		w.b.WriteString(" ")
+		w.number(p[3])
+	case wktXYZM:
+		w.b.WriteString(" ")
+		w.number(p[2])
+		w.b.WriteString(" ")
+		w.number(p[3])
+	}
+
+	return nil
+}
+
+func (w *wktWriter) positions(points []Point) error {
+	if len(points) == 0 {
+		w.b.WriteString("EMPTY")
+		return nil
+	}
+
+	w.b.WriteString("(")
+	for i, p := range points {
+		if i > 0 {
+			w.b.WriteString(", ")
+		}
+		if err := w.position(p); err != nil {
+			return err
+		}
+	}
+	w.b.WriteString(")")
+
+	return nil
+}
+
+func (w *wktWriter) paths(paths [][]Point) error {
+	if len(paths) == 0 {
+		w.b.WriteString("EMPTY")
+		return nil
+	}
+
+	w.b.WriteString("(")
+	for i, path := range paths {
+		if i > 0 {
+			w.b.WriteString(", ")
+		}
+		if err := w.positions(path); err != nil {
+			return err
+		}
+	}
+	w.b.WriteString(")")
+
+	return nil
+}
+
+// UnmarshalWKT decodes Well-Known Text, e.g. POINT (40 5), into a geometry.
+// Tags are case insensitive, Z, M and ZM dimensions and an EWKT SRID=<srid>; prefix are accepted.
+func UnmarshalWKT(s string) (*Geometry, error) {
+	if i := strings.IndexByte(s, ';'); i >= 0 && strings.HasPrefix(strings.ToUpper(strings.TrimSpace(s)), "SRID=") {
+		s = s[i+1:]
+	}
+
+	tokens, err := lexWKT(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &wktParser{tokens: tokens}
+	g, err := p.geometry()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, fmt.Errorf("wkt: unexpected %q after geometry", p.tokens[p.pos])
+	}
+
+	return g, nil
+}
+
+func lexWKT(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		c := s[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '(' || c == ')' || c == ',':
+			tokens = append(tokens, s[i:i+1])
+			i++
+		case isWKTLetter(c) || isWKTNumber(c):
+			j := i
+			for j < len(s) && (isWKTLetter(s[j]) || isWKTNumber(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		default:
+			return nil, fmt.Errorf("wkt: unexpected character %q", c)
+		}
+	}
+
+	return tokens, nil
+}
+
+func isWKTLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWKTNumber(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '.' || c == '-' || c == '+'
+}
+
+type wktParser struct {
+	tokens []string
+	pos    int
+	dim    wktDimension
+	tagged bool
+}
+
+func (p *wktParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *wktParser) expect(token string) error {
+	if got := p.peek(); got != token {
+		if got == "" {
+			return fmt.Errorf("wkt: expected %q, got end of input", token)
+		}
+		return fmt.Errorf("wkt: expected %q, got %q", token, got)
+	}
+	p.pos++
+
+	return nil
+}
+
+// empty consumes an EMPTY keyword.
+func (p *wktParser) empty() bool {
+	if strings.EqualFold(p.peek(), "EMPTY") {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) geometry() (*Geometry, error) {
+	word := strings.ToUpper(p.peek())
+	p.pos++
+
+	var t GeometryType
+	for gt, tag := range wktTags {
+		if strings.HasPrefix(word, tag) {
+			if suffix := word[len(tag):]; suffix == "" || suffix == "Z" || suffix == "M" || suffix == "ZM" {
+				t = gt
+				p.setDimension(suffix)
+				break
+			}
+		}
+	}
+	if t == "" {
+		return nil, fmt.Errorf("wkt: unknown geometry type %q", word)
+	}
+
+	if !p.tagged {
+		switch strings.ToUpper(p.peek()) {
+		case "Z", "M", "ZM":
+			p.setDimension(strings.ToUpper(p.peek()))
+			p.pos++
+		}
+	}
+
+	g := &Geometry{Type: t}
+	if p.empty() {
+		return g, nil
+	}
+
+	var err error
+	switch t {
+	case GeometryPoint:
+		var points []Point
+		points, err = p.positions()
+		if err == nil && len(points) != 1 {
+			err = fmt.Errorf("wkt: point must have exactly one position, got %d", len(points))
+		}
+		if err == nil {
+			g.Point = points[0]
+		}
+	case GeometryMultiPoint:
+		g.MultiPoint, err = p.multiPoint()
+	case GeometryLineString:
+		g.LineString, err = p.positions()
+	case GeometryMultiLineString:
+		g.MultiLineString, err = p.paths()
+	case GeometryPolygon:
+		g.Polygon, err = p.paths()
+	case GeometryMultiPolygon:
+		g.MultiPolygon, err = p.polygons()
+	case GeometryCollection:
+		g.Geometries, err = p.geometries()
+	}
+	p.tagged = false
+	if err != nil {
+		return nil, err
+	}
+
+	return g, nil
+}
+
+func (p *wktParser) setDimension(tag string) {
+	p.tagged = tag != ""
+	switch tag {
+	case "Z":
+		p.dim = wktXYZ
+	case "M":
+		p.dim = wktXYM
+	case "ZM":
+		p.dim = wktXYZM
+	default:
+		p.dim = wktXY
+	}
+}
+
+func (p *wktParser) position() (Point, error) {
+	var values []float64
+	for p.pos < len(p.tokens) {
+		token := p.tokens[p.pos]
+		if token == "," || token == ")" {
+			break
+		}
+		f, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, fmt.Errorf("wkt: not a valid coordinate, got %q", token)
+		}
+		values = append(values, f)
+		p.pos++
+	}
+
+	if !p.tagged {
+		if len(values) < 2 || len(values) > 4 {
+			return nil, fmt.Errorf("wkt: position must have 2 to 4 coordinates, got %d", len(values))
+		}
+		return values, nil
+	}
+	if len(values) != p.dim.size() {
+		return nil, fmt.Errorf("wkt: position must have %d coordinates, got %d", p.dim.size(), len(values))
+	}
+	if p.dim == wktXYM {
+		return Point{values[0], values[1], math.NaN(), values[2]}, nil
+	}
+
+	return values, nil
+}
+
+// list parses a comma separated list enclosed in parentheses.
+func (p *wktParser) list(item func() error) error {
+	if err := p.expect("("); err != nil {
+		return err
+	}
+	for {
+		if err := item(); err != nil {
+			return err
+		}
+		if p.peek() != "," {
+			break
+		}
+		p.pos++
+	}
+
+	return p.expect(")")
+}
+
+// positions parses a line or a ring, EMPTY when it has no positions.
+func (p *wktParser) positions() ([]Point, error) {
+	if p.empty() {
+		return []Point{}, nil
+	}
+
+	var points []Point
+	err := p.list(func() error {
+		point, err := p.position()
+		points = append(points, point)
+		return err
+	})
+
+	return points, err
+}
+
+// multiPoint accepts both MULTIPOINT ((1 2), (3 4)) and MULTIPOINT (1 2, 3 4),
+// an EMPTY point has no elements.
+func (p *wktParser) multiPoint() ([]Point, error) {
+	var points []Point
+	err := p.list(func() error {
+		if p.empty() {
+			points = append(points, Point{})
+			return nil
+		}
+		if p.peek() != "(" {
+			point, err := p.position()
+			points = append(points, point)
+			return err
+		}
+		point, err := p.positions()
+		if err == nil && len(point) != 1 {
+			err = fmt.Errorf("wkt: point must have exactly one position, got %d", len(point))
+		}
+		if err == nil {
+			points = append(points, point[0])
+		}
+		return err
+	})
+
+	return points, err
+}
+
+// paths parses the lines of a multi-line string or the rings of a polygon,
+// EMPTY when it has none.
+func (p *wktParser) paths() ([][]Point, error) {
+	if p.empty() {
+		return [][]Point{}, nil
+	}
+
+	var paths [][]Point
+	err := p.list(func() error {
+		path, err := p.positions()
+		paths = append(paths, path)
+		return err
+	})
+
+	return paths, err
+}
+
+func (p *wktParser) polygons() ([][][]Point, error) {
+	var polygons [][][]Point
+	err := p.list(func() error {
+		polygon, err := p.paths()
+		polygons = append(polygons, polygon)
+		return err
+	})
+
+	return polygons, err
+}
+
+func (p *wktParser) geometries() ([]*Geometry, error) {
+	var geometries []*Geometry
+	err := p.list(func() error {
+		g, err := p.geometry()
+		geometries = append(geometries, g)
+		return err
+	})
+
+	return geometries, err
+}
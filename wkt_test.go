@@ -0,0 +1,177 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+)
+
+func TestMarshalWKT(t *testing.T) {
+	cases := []struct {
+		g    *Geometry
+		want string
+	}{
+		{NewPoint(Point{1, 2}), "POINT (1 2)"},
+		{NewPoint(Point{1.5, -2, 3}), "POINT Z (1.5 -2 3)"},
+		{NewPoint(Point{1, 2, math.NaN(), 4}), "POINT M (1 2 4)"},
+		{NewPoint(Point{1, 2, 3, 4}), "POINT ZM (1 2 3 4)"},
+		{&Geometry{Type: GeometryPoint}, "POINT EMPTY"},
+		{NewMultiPoint(Point{1, 2}, Point{3, 4}), "MULTIPOINT ((1 2), (3 4))"},
+		{NewMultiPoint(nil, Point{1, 2}), "MULTIPOINT (EMPTY, (1 2))"},
+		{NewLineString([]Point{{1, 2}, {3, 4}}), "LINESTRING (1 2, 3 4)"},
+		{NewMultiLineString([]Point{{1, 2}, {3, 4}}, []Point{{5, 6}, {7, 8}}), "MULTILINESTRING ((1 2, 3 4), (5 6, 7 8))"},
+		{NewPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}}), "POLYGON ((0 0, 3 6, 6 1, 0 0))"},
+		{NewMultiPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}}, [][]Point{{{1, 1}, {2, 2}, {3, 1}, {1, 1}}}), "MULTIPOLYGON (((0 0, 3 6, 6 1, 0 0)), ((1 1, 2 2, 3 1, 1 1)))"},
+		{NewGeometryCollection(NewPoint(Point{1, 2}), NewLineString([]Point{{1, 2}, {3, 4}})), "GEOMETRYCOLLECTION (POINT (1 2), LINESTRING (1 2, 3 4))"},
+		{NewGeometryCollection(), "GEOMETRYCOLLECTION EMPTY"},
+	}
+
+	for _, c := range cases {
+		got, err := c.g.MarshalWKT()
+		if err != nil {
+			t.Fatalf("should marshal %s just fine but got %v", c.want, err)
+		}
+
+		if got != c.want {
+			t.Errorf("incorrect wkt, got %s, want %s", got, c.want)
+		}
+	}
+
+	if _, err := NewLineString([]Point{{1, 2}, {3, 4, 5}}).MarshalWKT(); err == nil {
+		t.Errorf("should fail to marshal mixed dimensions")
+	}
+
+	for _, g := range []*Geometry{
+		NewPoint(Point{1, 2, 3, 4, 5}),
+		NewLineString([]Point{{1, 2, 3, 4}, {1, 2, 3, 4, 5}}),
+		NewLineString([]Point{{1, 2}, {3}}),
+	} {
+		if s, err := g.MarshalWKT(); err == nil {
+			t.Errorf("should fail to marshal positions of 1 or 5 elements, got %s", s)
+		}
+	}
+
+	g := NewPolygon([][]Point{{{0, 0}, {3, 6}, {6, 1}, {0, 0}}, {}})
+	s, err := g.MarshalWKT()
+	if err != nil || s != "POLYGON ((0 0, 3 6, 6 1, 0 0), EMPTY)" {
+		t.Fatalf("should write an empty ring as EMPTY, got %s, %v", s, err)
+	}
+
+	back, err := UnmarshalWKT(s)
+	if err != nil || len(back.Polygon) != 2 || len(back.Polygon[1]) != 0 {
+		t.Errorf("should read back the empty ring, got %+v, %v", back, err)
+	}
+}
+
+func TestUnmarshalWKT(t *testing.T) {
+	for _, s := range []string{
+		"POINT (1 2)",
+		"POINT Z (1.5 -2 3)",
+		"POINT M (1 2 4)",
+		"POINT ZM (1 2 3 4)",
+		"POINT EMPTY",
+		"MULTIPOINT ((1 2), (3 4))",
+		"LINESTRING (1 2, 3 4)",
+		"MULTILINESTRING ((1 2, 3 4), (5 6, 7 8))",
+		"POLYGON ((0 0, 3 6, 6 1, 0 0))",
+		"MULTIPOLYGON (((0 0, 3 6, 6 1, 0 0)), ((1 1, 2 2, 3 1, 1 1)))",
+		"GEOMETRYCOLLECTION (POINT (1 2), LINESTRING (1 2, 3 4))",
+		"GEOMETRYCOLLECTION EMPTY",
+		"GEOMETRYCOLLECTION Z (POINT Z (1 2 3))",
+		"GEOMETRYCOLLECTION ZM (GEOMETRYCOLLECTION EMPTY, GEOMETRYCOLLECTION ZM (POINT EMPTY, LINESTRING ZM (1 2 3 4, 5 6 7 8)))",
+		"POLYGON ((0 0, 3 6, 6 1, 0 0), EMPTY)",
+		"MULTIPOLYGON (EMPTY, ((1 1, 2 2, 3 1, 1 1), EMPTY))",
+		"MULTILINESTRING (EMPTY, (1 2, 3 4))",
+		"MULTIPOINT ((1 2), EMPTY)",
+		"MULTIPOINT (EMPTY, (1 2))",
+		"MULTIPOINT Z (EMPTY, (1 2 3))",
+		"MULTILINESTRING M (EMPTY, (1 2 3, 4 5 6))",
+		"MULTIPOLYGON Z (EMPTY, ((0 0 1, 3 6 1, 6 1 1, 0 0 1)))",
+	} {
+		g, err := UnmarshalWKT(s)
+		if err != nil {
+			t.Fatalf("should unmarshal %s without issue, err %v", s, err)
+		}
+
+		got, err := g.MarshalWKT()
+		if err != nil {
+			t.Fatalf("should marshal %s just fine but got %v", s, err)
+		}
+
+		if got != s {
+			t.Errorf("wkt should round trip, got %s, want %s", got, s)
+		}
+	}
+}
+
+func TestWKTEmptyMembersThroughWKB(t *testing.T) {
+	for _, s := range []string{
+		"MULTIPOINT (EMPTY, (1 2))",
+		"MULTIPOINT Z (EMPTY, (1 2 3))",
+		"MULTILINESTRING ZM (EMPTY, (1 2 3 4, 5 6 7 8))",
+		"MULTIPOLYGON Z (EMPTY, ((0 0 1, 3 6 1, 6 1 1, 0 0 1)))",
+	} {
+		g, err := UnmarshalWKT(s)
+		if err != nil {
+			t.Fatalf("should unmarshal %s without issue, err %v", s, err)
+		}
+
+		data, err := g.MarshalWKB(binary.LittleEndian)
+		if err != nil {
+			t.Fatalf("should marshal %s to wkb just fine but got %v", s, err)
+		}
+
+		back, _, err := UnmarshalWKB(data)
+		if err != nil {
+			t.Fatalf("should unmarshal %s from wkb without issue, err %v", s, err)
+		}
+
+		got, err := back.MarshalWKT()
+		if err != nil {
+			t.Fatalf("should marshal %s just fine but got %v", s, err)
+		}
+
+		if got != s {
+			t.Errorf("wkt should round trip through wkb, got %s, want %s", got, s)
+		}
+	}
+}
+
+func TestUnmarshalWKTVariants(t *testing.T) {
+	cases := []struct {
+		s    string
+		want string
+	}{
+		{"point(1 2)", "POINT (1 2)"},
+		{"POINTZ(1 2 3)", "POINT Z (1 2 3)"},
+		{"POINT (1 2 3)", "POINT Z (1 2 3)"},
+		{"SRID=4326;POINT(1e1 2)", "POINT (10 2)"},
+		{"MULTIPOINT (1 2, 3 4)", "MULTIPOINT ((1 2), (3 4))"},
+		{"GEOMETRYCOLLECTION (POINT EMPTY, POINT M (1 2 3))", "GEOMETRYCOLLECTION M (POINT EMPTY, POINT M (1 2 3))"},
+	}
+
+	for _, c := range cases {
+		g, err := UnmarshalWKT(c.s)
+		if err != nil {
+			t.Fatalf("should unmarshal %s without issue, err %v", c.s, err)
+		}
+
+		got, _ := g.MarshalWKT()
+		if got != c.want {
+			t.Errorf("incorrect wkt, got %s, want %s", got, c.want)
+		}
+	}
+
+	for _, s := range []string{
+		"CIRCLE (1 2)",
+		"POINT (1)",
+		"POINT Z (1 2)",
+		"POINT (1 2",
+		"LINESTRING (1 2, 3 a)",
+		"POINT (1 2) POINT",
+	} {
+		if _, err := UnmarshalWKT(s); err == nil {
+			t.Errorf("should fail to unmarshal %s", s)
+		}
+	}
+}